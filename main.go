@@ -8,6 +8,7 @@ import (
 
 	"demo-go-tiny/src/use_echo"
 	"demo-go-tiny/src/use_gin"
+	"demo-go-tiny/src/use_grpc"
 	"demo-go-tiny/src/use_http"
 	"demo-go-tiny/src/use_mux"
 )
@@ -18,6 +19,11 @@ func main() {
 	flag.StringVar(&framework, "c", "gin", "Specify web framework: gin, echo, mux, http")
 	flag.Parse()
 
+	// 可选：并行启动 gRPC 服务
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		go use_grpc.StartServer(grpcPort)
+	}
+
 	// 启动对应的框架服务
 	switch framework {
 	case "gin":
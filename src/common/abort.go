@@ -0,0 +1,78 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ---------- 异常断开连接（验证网关 502 的上报与重试） ----------
+
+// Abort 接管连接后按模式异常结束，只支持 HTTP/1.x
+//
+//	/abort/close                       不发送任何响应直接关闭（FIN）
+//	/abort/reset                       不发送任何响应直接 RST
+//	/abort/midbody?size=1024           声明 size 字节，只发送一半后关闭
+//	/abort/wrong-length?delta=100      Content-Length 比实际 body 多 delta 字节（负数则少），delay 后关闭；
+//	                                   声明偏少时多出的字节会被复用连接的客户端当作下一个响应
+//	/abort/malformed                   发送无法解析的状态行与响应头后关闭
+//	/abort/keepalive-close?delay=0     正常响应并保持 keep-alive，delay 后不带任何通知关闭连接
+func Abort(w http.ResponseWriter, r *http.Request) {
+	mode := PathParam(r, "/abort/")
+	switch mode {
+	case "close", "reset", "midbody", "wrong-length", "malformed", "keepalive-close":
+	default:
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("unknown mode: %q, available: close reset midbody wrong-length malformed keepalive-close", mode)})
+		return
+	}
+	size := int64(QueryInt(r, "size", 1024))
+	delta := int64(QueryInt(r, "delta", 100))
+	delay := queryDuration(r, "delay", 0)
+	if size < 0 || size > bytesMaxSize {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("invalid size, max %d", bytesMaxSize)})
+		return
+	}
+
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		WriteJSON(w, http.StatusNotImplemented, Resp{Code: 1, Msg: "cannot hijack " + r.Proto + " connection: " + err.Error()})
+		return
+	}
+
+	// body 按需生成，不在内存中拼出完整内容
+	body := func(n int64) {
+		_, _ = io.Copy(buf, &payload{size: n, pattern: []byte(".")})
+	}
+	head := func(length int64) string {
+		return fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: %d\r\nX-Pod-Name: %s\r\n\r\n", length, PodName())
+	}
+	switch mode {
+	case "close":
+		_ = conn.Close()
+	case "reset":
+		rstClose(conn)
+	case "midbody":
+		_, _ = buf.WriteString(head(size))
+		body(size / 2)
+		_ = buf.Flush()
+		_ = conn.Close()
+	case "wrong-length":
+		_, _ = buf.WriteString(head(max(size+delta, 0)))
+		body(size)
+		_ = buf.Flush()
+		time.Sleep(delay)
+		_ = conn.Close()
+	case "malformed":
+		_, _ = buf.WriteString("HTTP/1.1 2x0 NOT-HTTP\r\nthis is not a header\r\n\r\n\x00\x01garbage")
+		_ = buf.Flush()
+		_ = conn.Close()
+	case "keepalive-close":
+		_, _ = buf.WriteString(strings.Replace(head(size), "\r\n\r\n", "\r\nConnection: keep-alive\r\n\r\n", 1))
+		body(size)
+		_ = buf.Flush()
+		time.Sleep(delay)
+		_ = conn.Close()
+	}
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ---------- 管理接口鉴权 ----------
+
+// adminToken 管理接口（/admin/*）的令牌，由 ADMIN_TOKEN 指定，未设置时不鉴权
+var adminToken = os.Getenv("ADMIN_TOKEN")
+
+// adminAllowed 校验 Authorization: Bearer <token> 或 X-Admin-Token，失败时写出 401
+func adminAllowed(w http.ResponseWriter, r *http.Request) bool {
+	if adminToken == "" {
+		return true
+	}
+	token := r.Header.Get("X-Admin-Token")
+	if v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = v
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+	WriteJSON(w, http.StatusUnauthorized, Resp{Code: 1, Msg: "invalid admin token"})
+	return false
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ---------- 任意路径回显（验证 proxy-rewrite regex_uri、前缀剥离后的上游 URI） ----------
+
+// AnythingFallback 为 true 时所有未匹配的路径都按 /anything 回显，而不是 404，由 ANYTHING_FALLBACK=true 开启
+var AnythingFallback = os.Getenv("ANYTHING_FALLBACK") == "true"
+
+// Anything 回显收到的最终路径、原始路径、Query 与方法，以及 /echo 的全部内容
+//
+//	/anything/any/path?x=1   任意方法、任意子路径
+func Anything(w http.ResponseWriter, r *http.Request) {
+	data, err := EchoData(r)
+	if err != nil {
+		WriteJSON(w, http.StatusRequestEntityTooLarge, Resp{Code: 1, Msg: err.Error()})
+		return
+	}
+	data["raw_path"] = r.URL.EscapedPath()
+	data["raw_query"] = r.URL.RawQuery
+	data["matched"] = "anything"
+	if r.URL.Path != "/anything" && !strings.HasPrefix(r.URL.Path, "/anything/") {
+		data["matched"] = "fallback"
+	}
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: data})
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ---------- 大响应体生成（验证代理缓冲、大小限制与完整性） ----------
+
+// bytesMaxSize 单次生成的最大字节数，由 BYTES_MAX_SIZE 指定，默认 1 GiB
+var bytesMaxSize = int64(EnvInt("BYTES_MAX_SIZE", 1<<30))
+
+// payload 可随机访问的伪随机或重复图案内容，用于配合 Range 请求
+type payload struct {
+	size    int64
+	seed    uint64
+	pattern []byte
+	off     int64
+}
+
+func (p *payload) Read(b []byte) (int, error) {
+	n, err := p.ReadAt(b, p.off)
+	p.off += int64(n)
+	return n, err
+}
+
+func (p *payload) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += p.off
+	case io.SeekEnd:
+		offset += p.size
+	default:
+		return 0, errors.New("payload: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("payload: negative position")
+	}
+	p.off = offset
+	return offset, nil
+}
+
+// ReadAt 第 i 个字节只由 seed（或 pattern）和 i 决定，因此任意区间都可重复生成
+func (p *payload) ReadAt(b []byte, off int64) (int, error) {
+	if off >= p.size {
+		return 0, io.EOF
+	}
+	var err error
+	if rem := p.size - off; int64(len(b)) > rem {
+		b, err = b[:rem], io.EOF
+	}
+	if len(p.pattern) > 0 {
+		for i := range b {
+			b[i] = p.pattern[(off+int64(i))%int64(len(p.pattern))]
+		}
+		return len(b), err
+	}
+	for i := 0; i < len(b); {
+		pos := off + int64(i)
+		word := splitmix64(p.seed + uint64(pos/8))
+		for j := pos % 8; j < 8 && i < len(b); j++ {
+			b[i] = byte(word >> (8 * j))
+			i++
+		}
+	}
+	return len(b), err
+}
+
+// newPayload 按路径中的大小与 seed / pattern 参数构造内容，并写出 X-Seed、ETag 等响应头
+func newPayload(w http.ResponseWriter, r *http.Request, prefix string) (*payload, bool) {
+	size, err := parseSize(PathParam(r, prefix))
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: err.Error()})
+		return nil, false
+	}
+	if size > bytesMaxSize {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("size %d exceeds BYTES_MAX_SIZE %d", size, bytesMaxSize)})
+		return nil, false
+	}
+
+	p := &payload{size: size, pattern: []byte(r.URL.Query().Get("pattern"))}
+	if v := r.URL.Query().Get("seed"); v != "" {
+		if p.seed, err = strconv.ParseUint(v, 10, 64); err != nil {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "invalid seed: " + v})
+			return nil, false
+		}
+	} else {
+		p.seed = rand.Uint64()
+	}
+
+	f := fnv.New64a()
+	f.Write(p.pattern)
+	h := w.Header()
+	h.Set("Content-Type", "application/octet-stream")
+	h.Set("ETag", fmt.Sprintf(`"%d-%d-%x"`, size, p.seed, f.Sum64()))
+	if len(p.pattern) == 0 {
+		h.Set("X-Seed", strconv.FormatUint(p.seed, 10))
+	}
+	return p, true
+}
+
+// Bytes 返回固定 Content-Length 的内容，支持 Range
+//
+//	/bytes/{n}?seed=42       可复现的伪随机内容，不指定 seed 时随机并通过 X-Seed 返回
+//	/bytes/{n}?pattern=abc   重复图案内容
+//	/bytes/{n}?checksum=1    在 X-Content-Sha256 中返回完整内容的校验和，需先完整计算一遍，大内容会明显推迟首字节
+func Bytes(w http.ResponseWriter, r *http.Request) {
+	p, ok := newPayload(w, r, "/bytes/")
+	if !ok {
+		return
+	}
+	if checksum, _ := strconv.ParseBool(r.URL.Query().Get("checksum")); checksum {
+		sum := sha256.New()
+		if _, err := io.Copy(sum, &payload{size: p.size, seed: p.seed, pattern: p.pattern}); err != nil {
+			WriteJSON(w, http.StatusInternalServerError, Resp{Code: 1, Msg: err.Error()})
+			return
+		}
+		w.Header().Set("X-Content-Sha256", hex.EncodeToString(sum.Sum(nil)))
+	}
+	http.ServeContent(w, r, "", time.Time{}, p)
+}
+
+// StreamBytes 以 chunked 编码分块发送，支持 Range；实际发送内容的校验和放在 X-Content-Sha256 trailer 中
+//
+//	/stream-bytes/{n}?chunk_size=16384&seed=42
+func StreamBytes(w http.ResponseWriter, r *http.Request) {
+	p, ok := newPayload(w, r, "/stream-bytes/")
+	if !ok {
+		return
+	}
+	chunk := QueryInt(r, "chunk_size", 16*1024)
+	if chunk <= 0 {
+		chunk = 16 * 1024
+	}
+
+	w.Header().Set("Trailer", "X-Content-Sha256")
+	sw := &chunkWriter{ResponseWriter: w, rc: http.NewResponseController(w), sum: sha256.New(), chunk: chunk}
+	http.ServeContent(sw, r, "", time.Time{}, p)
+	w.Header().Set("X-Content-Sha256", hex.EncodeToString(sw.sum.Sum(nil)))
+}
+
+// chunkWriter 去掉 Content-Length 并逐块 Flush，迫使 net/http 使用 chunked 编码
+type chunkWriter struct {
+	http.ResponseWriter
+	rc    *http.ResponseController
+	sum   hash.Hash
+	chunk int
+}
+
+func (c *chunkWriter) WriteHeader(code int) {
+	c.Header().Del("Content-Length")
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *chunkWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		n := min(c.chunk, len(b))
+		m, err := c.ResponseWriter.Write(b[:n])
+		c.sum.Write(b[:m])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		if err := c.rc.Flush(); err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
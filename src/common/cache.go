@@ -0,0 +1,154 @@
+package common
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------- HTTP 缓存语义（验证 APISIX proxy-cache） ----------
+
+const (
+	// cacheHitsTTL 超过该时长未访问的 URI 不再统计
+	cacheHitsTTL = time.Hour
+	// cacheHitsMax 最多统计的 URI 数，避免不同查询串无限增长
+	cacheHitsMax = 10000
+)
+
+// cacheHit 单个 URI 的处理次数与最后访问时间
+type cacheHit struct {
+	n    int64
+	last time.Time
+}
+
+// cacheHits 按请求 URI 统计本实例实际处理的次数；网关命中缓存时计数不会增加
+var cacheHits = struct {
+	sync.Mutex
+	m map[string]*cacheHit
+}{m: make(map[string]*cacheHit)}
+
+func incCacheHit(key string) int64 {
+	cacheHits.Lock()
+	defer cacheHits.Unlock()
+	now := time.Now()
+	c, ok := cacheHits.m[key]
+	if !ok {
+		if len(cacheHits.m) >= cacheHitsMax {
+			for k, v := range cacheHits.m {
+				if now.Sub(v.last) > cacheHitsTTL {
+					delete(cacheHits.m, k)
+				}
+			}
+		}
+		// 仍然满时随机淘汰一个
+		for k := range cacheHits.m {
+			if len(cacheHits.m) < cacheHitsMax {
+				break
+			}
+			delete(cacheHits.m, k)
+		}
+		c = &cacheHit{}
+		cacheHits.m[key] = c
+	}
+	c.n++
+	c.last = now
+	return c.n
+}
+
+// Cache 返回带 Cache-Control / Expires / ETag / Last-Modified / Vary 的可缓存响应，
+// 并按 If-None-Match / If-Modified-Since 返回 304
+//
+//	/cache?max_age=60          缓存秒数，默认 60
+//	/cache/{seconds}           同上，路径写法
+//	/cache?cc=no-store         直接指定 Cache-Control
+//	/cache?vary=Accept-Encoding 指定 Vary，ETag 会随对应请求头变化
+func Cache(w http.ResponseWriter, r *http.Request) {
+	maxAge := 60
+	s := PathParam(r, "/cache/")
+	if s == "" {
+		s = r.URL.Query().Get("max_age")
+	}
+	if s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "invalid seconds: " + s})
+			return
+		}
+		maxAge = n
+	}
+
+	key := r.URL.RequestURI()
+	hits := incCacheHit(key)
+	lastModified := StartTime.UTC().Truncate(time.Second)
+	vary := r.URL.Query().Get("vary")
+	etag := cacheETag(r, key, vary)
+
+	h := w.Header()
+	cc := r.URL.Query().Get("cc")
+	if cc == "" {
+		cc = fmt.Sprintf("public, max-age=%d", maxAge)
+	}
+	h.Set("Cache-Control", cc)
+	h.Set("Expires", time.Now().Add(time.Duration(maxAge)*time.Second).UTC().Format(http.TimeFormat))
+	h.Set("ETag", etag)
+	h.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	if vary != "" {
+		h.Add("Vary", vary)
+	}
+	h.Set("X-Hit-Count", strconv.FormatInt(hits, 10))
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: map[string]interface{}{
+		"key":           key,
+		"hits":          hits,
+		"pod":           PodName(),
+		"version":       Version(),
+		"cache_control": cc,
+		"etag":          etag,
+		"last_modified": lastModified.Format(http.TimeFormat),
+		"generated_at":  time.Now().Format(time.RFC3339Nano),
+	}})
+}
+
+// cacheETag 由 URI、版本以及 Vary 指定的请求头计算，同一资源在同版本实例间保持一致
+func cacheETag(r *http.Request, key, vary string) string {
+	f := fnv.New64a()
+	f.Write([]byte(key))
+	f.Write([]byte(Version()))
+	for _, name := range strings.Split(vary, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			f.Write([]byte(r.Header.Get(name)))
+		}
+	}
+	return fmt.Sprintf(`"%x"`, f.Sum64())
+}
+
+// notModified 按 RFC 9110 判断条件请求：If-None-Match 优先于 If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == "*" || t == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !lastModified.After(t)
+		}
+	}
+	return false
+}
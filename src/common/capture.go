@@ -0,0 +1,425 @@
+package common
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ---------- 请求录制（配合 APISIX proxy-mirror 与排障） ----------
+
+var (
+	// captureSize 保留最近的请求数，由 CAPTURE_SIZE 指定，默认 0 关闭（录制内容含其他客户端的请求，需显式开启）
+	captureSize = EnvInt("CAPTURE_SIZE", 0)
+	// captureBodyMax 每个请求 / 响应体最多保留的字节数，由 CAPTURE_BODY_MAX 指定，默认 4096
+	captureBodyMax = EnvInt("CAPTURE_BODY_MAX", 4096)
+	// captureFile 设置后每条记录额外以 JSON Lines 追加写入该文件，由 CAPTURE_FILE 指定
+	captureFile = os.Getenv("CAPTURE_FILE")
+)
+
+// capturedSecretHeaders 录制时隐去值的凭据类请求 / 响应头
+var capturedSecretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Admin-Token", "X-Api-Key"}
+
+// capturedNoBodyPaths 不录制响应体的路径（如含私钥的客户端证书）
+var capturedNoBodyPaths = []string{"/tls/client-cert"}
+
+// redactHeaders 返回隐去凭据后的副本
+func redactHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range capturedSecretHeaders {
+		if vs := h.Values(k); len(vs) > 0 {
+			h[http.CanonicalHeaderKey(k)] = []string{"[REDACTED]"}
+		}
+	}
+	return h
+}
+
+// capturedMessage 请求或响应的头与截断后的 body
+type capturedMessage struct {
+	Headers      http.Header `json:"headers"`
+	Body         string      `json:"body"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+	BodySize     int64       `json:"body_size"`
+	Truncated    bool        `json:"truncated"`
+}
+
+// capturedRequest 一条录制记录
+type capturedRequest struct {
+	ID         int64           `json:"id"`
+	Time       time.Time       `json:"time"`
+	DurationMs float64         `json:"duration_ms"`
+	TTFBMs     float64         `json:"ttfb_ms"`
+	Method     string          `json:"method"`
+	URL        string          `json:"url"`
+	Host       string          `json:"host"`
+	Path       string          `json:"path"`
+	Proto      string          `json:"proto"`
+	RemoteAddr string          `json:"remote_addr"`
+	Client     string          `json:"client"`
+	Status     int             `json:"status"`
+	Request    capturedMessage `json:"request"`
+	Response   capturedMessage `json:"response"`
+}
+
+var captures = struct {
+	sync.Mutex
+	seq  int64
+	ring []*capturedRequest
+	next int
+	file *json.Encoder
+}{}
+
+// bodyBuffer 只保留前 captureBodyMax 字节，同时统计总长度
+type bodyBuffer struct {
+	buf  []byte
+	size int64
+}
+
+func (b *bodyBuffer) Write(p []byte) (int, error) {
+	b.size += int64(len(p))
+	if room := captureBodyMax - len(b.buf); room > 0 {
+		b.buf = append(b.buf, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
+
+func (b *bodyBuffer) message(h http.Header) capturedMessage {
+	m := capturedMessage{Headers: h, BodySize: b.size, Truncated: b.size > int64(len(b.buf))}
+	if utf8.Valid(b.buf) {
+		m.Body = string(b.buf)
+	} else {
+		m.Body = base64.StdEncoding.EncodeToString(b.buf)
+		m.BodyEncoding = "base64"
+	}
+	return m
+}
+
+// captureReadWait 处理函数返回后补读请求体的最长等待
+const captureReadWait = 100 * time.Millisecond
+
+// teeBody 把处理函数读到的请求体写入 buf，并记录是否已读到结尾
+type teeBody struct {
+	io.ReadCloser
+	buf *bodyBuffer
+	eof bool
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	_, _ = b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.eof = true
+	}
+	return n, err
+}
+
+// captureWriter 记录状态码、首字节时间与响应体
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	first  time.Time
+	body   bodyBuffer
+}
+
+func (w *captureWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+		w.first = time.Now()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+		w.first = time.Now()
+	}
+	n, err := w.ResponseWriter.Write(p)
+	_, _ = w.body.Write(p[:n])
+	return n, err
+}
+
+func (w *captureWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack 接管连接（如 WebSocket）后只记录握手
+func (w *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+		w.first = time.Now()
+	}
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// capture 录制经过的请求，管理接口自身的请求不录制
+func capture(next http.Handler) http.Handler {
+	if captureSize <= 0 && captureFile == "" {
+		return next
+	}
+	if captureFile != "" {
+		f, err := os.OpenFile(captureFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatalf("Failed to open CAPTURE_FILE: %v", err)
+		}
+		captures.file = json.NewEncoder(f)
+	}
+	if captureSize > 0 {
+		captures.ring = make([]*capturedRequest, captureSize)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		reqHeader := redactHeaders(r.Header)
+		var reqBody bodyBuffer
+		var body *teeBody
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &teeBody{ReadCloser: r.Body, buf: &reqBody}
+			r.Body = body
+		}
+		cw := &captureWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r)
+
+		end := time.Now()
+		// 处理函数没读完 body 时（如 proxy-mirror 的镜像请求打到不读 body 的路由）补读到 captureBodyMax；
+		// 不提前读，避免抢先发出 100 Continue 或推迟处理函数开始的时间
+		if body != nil && !body.eof && len(reqBody.buf) < captureBodyMax && r.Header.Get("Expect") == "" {
+			rc := http.NewResponseController(w)
+			_ = rc.SetReadDeadline(time.Now().Add(captureReadWait))
+			_, _ = io.CopyN(io.Discard, body, int64(captureBodyMax-len(reqBody.buf)))
+			_ = rc.SetReadDeadline(time.Time{})
+		}
+		// 剩余部分未被读取时按 Content-Length 计算总长度
+		reqBody.size = max(reqBody.size, r.ContentLength)
+		if cw.status == 0 {
+			cw.status, cw.first = http.StatusOK, end
+		}
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		rec := &capturedRequest{
+			Time:       start,
+			DurationMs: float64(end.Sub(start).Microseconds()) / 1000,
+			TTFBMs:     float64(cw.first.Sub(start).Microseconds()) / 1000,
+			Method:     r.Method,
+			URL:        scheme + "://" + r.Host + r.RequestURI,
+			Host:       r.Host,
+			Path:       r.URL.Path,
+			Proto:      r.Proto,
+			RemoteAddr: r.RemoteAddr,
+			Client:     clientIP(r),
+			Status:     cw.status,
+			Request:    reqBody.message(reqHeader),
+			Response:   cw.body.message(redactHeaders(w.Header())),
+		}
+		for _, p := range capturedNoBodyPaths {
+			if r.URL.Path == p {
+				rec.Response.Body, rec.Response.BodyEncoding, rec.Response.Truncated = "", "", true
+			}
+		}
+
+		captures.Lock()
+		defer captures.Unlock()
+		captures.seq++
+		rec.ID = captures.seq
+		if captureSize > 0 {
+			captures.ring[captures.next] = rec
+			captures.next = (captures.next + 1) % captureSize
+		}
+		if captures.file != nil {
+			if err := captures.file.Encode(rec); err != nil {
+				log.Printf("capture file: %v", err)
+			}
+		}
+	})
+}
+
+// capturedList 按过滤条件返回记录，最新的在前
+func capturedList(r *http.Request) []*capturedRequest {
+	q := r.URL.Query()
+	method, path, client := q.Get("method"), q.Get("path"), q.Get("client")
+	status := QueryInt(r, "status", 0)
+	limit := QueryInt(r, "limit", 0)
+
+	captures.Lock()
+	defer captures.Unlock()
+	list := []*capturedRequest{}
+	for i := 1; i <= len(captures.ring); i++ {
+		rec := captures.ring[(captures.next-i+len(captures.ring))%len(captures.ring)]
+		if rec == nil {
+			break
+		}
+		if (method != "" && !strings.EqualFold(rec.Method, method)) ||
+			(path != "" && !strings.Contains(rec.Path, path)) ||
+			(client != "" && rec.Client != client) ||
+			(status != 0 && rec.Status != status) {
+			continue
+		}
+		list = append(list, rec)
+		if limit > 0 && len(list) >= limit {
+			break
+		}
+	}
+	return list
+}
+
+// Requests 查看录制的请求
+//
+//	GET    /admin/requests?method=POST&path=/echo&status=200&client=10.0.0.1&limit=20
+//	GET    /admin/requests/{id}
+//	GET    /admin/requests/har     以 HAR 1.2 导出，同样支持上述过滤参数
+//	DELETE /admin/requests         清空
+func Requests(w http.ResponseWriter, r *http.Request) {
+	if !adminAllowed(w, r) {
+		return
+	}
+	if captureSize <= 0 {
+		WriteJSON(w, http.StatusNotFound, Resp{Code: 1, Msg: "capture is disabled, set CAPTURE_SIZE"})
+		return
+	}
+
+	id := PathParam(r, "/admin/requests/")
+	switch {
+	case r.Method == http.MethodDelete && id == "":
+		captures.Lock()
+		n := 0
+		for i, rec := range captures.ring {
+			if rec != nil {
+				n++
+			}
+			captures.ring[i] = nil
+		}
+		captures.next = 0
+		captures.Unlock()
+		WriteJSON(w, http.StatusOK, Resp{Code: 0, Msg: "cleared " + strconv.Itoa(n) + " requests"})
+	case r.Method != http.MethodGet:
+		WriteJSON(w, http.StatusMethodNotAllowed, Resp{Code: 1, Msg: "method not allowed"})
+	case id == "":
+		WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: capturedList(r)})
+	case id == "har":
+		w.Header().Set("Content-Disposition", `attachment; filename="requests.har"`)
+		WriteJSON(w, http.StatusOK, harLog(capturedList(r)))
+	default:
+		n, _ := strconv.ParseInt(id, 10, 64)
+		captures.Lock()
+		var found *capturedRequest
+		for _, rec := range captures.ring {
+			if rec != nil && rec.ID == n {
+				found = rec
+				break
+			}
+		}
+		captures.Unlock()
+		if found == nil {
+			WriteJSON(w, http.StatusNotFound, Resp{Code: 1, Msg: "request " + id + " not found"})
+			return
+		}
+		WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: found})
+	}
+}
+
+// harLog 将记录转换为 HAR 1.2 格式
+func harLog(list []*capturedRequest) map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, len(list))
+	// HAR 按时间先后排列
+	for i := len(list) - 1; i >= 0; i-- {
+		rec := list[i]
+		query := []map[string]string{}
+		if u, err := url.Parse(rec.URL); err == nil {
+			for k, vs := range u.Query() {
+				for _, v := range vs {
+					query = append(query, map[string]string{"name": k, "value": v})
+				}
+			}
+		}
+		req := map[string]interface{}{
+			"method":      rec.Method,
+			"url":         rec.URL,
+			"httpVersion": rec.Proto,
+			"cookies":     []interface{}{},
+			"headers":     harHeaders(rec.Request.Headers),
+			"queryString": query,
+			"headersSize": -1,
+			"bodySize":    rec.Request.BodySize,
+		}
+		if rec.Request.BodySize > 0 {
+			req["postData"] = map[string]interface{}{
+				"mimeType": rec.Request.Headers.Get("Content-Type"),
+				"text":     rec.Request.Body,
+			}
+		}
+		content := map[string]interface{}{
+			"size":     rec.Response.BodySize,
+			"mimeType": rec.Response.Headers.Get("Content-Type"),
+			"text":     rec.Response.Body,
+		}
+		if rec.Response.BodyEncoding != "" {
+			content["encoding"] = rec.Response.BodyEncoding
+		}
+		entries = append(entries, map[string]interface{}{
+			"_id":             rec.ID,
+			"startedDateTime": rec.Time.Format(time.RFC3339Nano),
+			"time":            rec.DurationMs,
+			"request":         req,
+			"response": map[string]interface{}{
+				"status":      rec.Status,
+				"statusText":  http.StatusText(rec.Status),
+				"httpVersion": rec.Proto,
+				"cookies":     []interface{}{},
+				"headers":     harHeaders(rec.Response.Headers),
+				"content":     content,
+				"redirectURL": rec.Response.Headers.Get("Location"),
+				"headersSize": -1,
+				"bodySize":    rec.Response.BodySize,
+			},
+			"cache": map[string]interface{}{},
+			"timings": map[string]interface{}{
+				"send":    0,
+				"wait":    rec.TTFBMs,
+				"receive": rec.DurationMs - rec.TTFBMs,
+			},
+		})
+	}
+	return map[string]interface{}{"log": map[string]interface{}{
+		"version": "1.2",
+		"creator": map[string]string{"name": "demo-go-tiny", "version": Version()},
+		"entries": entries,
+	}}
+}
+
+func harHeaders(h http.Header) []map[string]string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := []map[string]string{}
+	for _, k := range keys {
+		for _, v := range h[k] {
+			out = append(out, map[string]string{"name": k, "value": v})
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+// Package common 存放与具体 Web 框架无关的 net/http 处理函数，
+// 由 use_gin / use_echo / use_mux / use_http 各自挂载，保证四种框架行为一致。
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resp 统一 JSON 返回结构
+type Resp struct {
+	Code int         `json:"code"`
+	Msg  string      `json:"msg"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// StartTime 进程启动时间
+var StartTime = time.Now()
+
+// WriteJSON 统一JSON响应函数
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// PodName 当前实例名称
+func PodName() string {
+	return os.Getenv("POD_NAME")
+}
+
+// Version 当前实例版本
+func Version() string {
+	return os.Getenv("VERSION")
+}
+
+// EnvInt 读取整型环境变量，未设置或非法时返回默认值
+func EnvInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// QueryInt 读取整型查询参数，未设置或非法时返回默认值
+func QueryInt(r *http.Request, key string, def int) int {
+	if v := r.URL.Query().Get(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// queryDuration 读取时长参数：纯数字按秒，也可写 500ms / 1m 等
+func queryDuration(r *http.Request, key string, def time.Duration) time.Duration {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return def
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		return time.Duration(n) * time.Second
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return d
+	}
+	return def
+}
+
+// clientIP 客户端IP获取函数，与各框架中的实现一致
+func clientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if xri := r.Header.Get("X-Real-Ip"); xri != "" {
+		return xri
+	}
+	return strings.Split(r.RemoteAddr, ":")[0]
+}
+
+// PathParam 取 prefix 之后的路径片段，例如 PathParam(r, "/cache/") 对 /cache/60 返回 "60"
+// 不依赖各框架自己的路径参数语法
+func PathParam(r *http.Request, prefix string) string {
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+}
+
+// parseSize 解析 1024 / 10k / 5m / 1g 形式的大小（按 1024 进制）
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "b")
+	mul := int64(1)
+	switch {
+	case strings.HasSuffix(s, "k"):
+		mul, s = 1<<10, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		mul, s = 1<<20, strings.TrimSuffix(s, "m")
+	case strings.HasSuffix(s, "g"):
+		mul, s = 1<<30, strings.TrimSuffix(s, "g")
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %q", s)
+	}
+	return n * mul, nil
+}
+
+// splitmix64 64 位混淆函数，用于由 seed 与位置生成可复现的伪随机数
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// waitCtx 等待 d，d <= 0 表示一直等到客户端断开；客户端断开时返回 false
+func waitCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		<-ctx.Done()
+		return false
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// exemptPaths 不参与限流与并发限制的路径前缀，避免探针与管理接口被拒绝
+var exemptPaths = []string{"/ping", "/metrics", "/health/", "/admin/"}
+
+func exempt(path string) bool {
+	for _, p := range exemptPaths {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// rstClose 以 RST 关闭连接，TLS 连接同样作用于底层 TCP
+func rstClose(conn net.Conn) {
+	raw := conn
+	if nc, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		raw = nc.NetConn()
+	}
+	if tc, ok := raw.(*net.TCPConn); ok {
+		_ = tc.SetLinger(0)
+	}
+	_ = conn.Close()
+}
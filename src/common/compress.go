@@ -0,0 +1,243 @@
+package common
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ---------- 响应压缩（验证网关 gzip / brotli 插件与重复压缩） ----------
+
+// encoder 各压缩算法的统一接口
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// encoders 支持的 Content-Encoding
+var encoders = map[string]func(w io.Writer) encoder{
+	"gzip":    func(w io.Writer) encoder { return gzip.NewWriter(w) },
+	"deflate": func(w io.Writer) encoder { return zlib.NewWriter(w) },
+	"br":      func(w io.Writer) encoder { return brotli.NewWriter(w) },
+	"zstd": func(w io.Writer) encoder {
+		e, _ := zstd.NewWriter(w)
+		return e
+	},
+}
+
+// compressEncodings 由 COMPRESS 指定，如 COMPRESS=br,zstd,gzip,deflate，顺序即服务端偏好；为空则不压缩
+var compressEncodings = parseEncodings(os.Getenv("COMPRESS"))
+
+// compressMinSize 小于该字节数的响应不压缩，由 COMPRESS_MIN_SIZE 指定
+var compressMinSize = EnvInt("COMPRESS_MIN_SIZE", 1024)
+
+func parseEncodings(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		e = normalizeEncoding(e)
+		if _, ok := encoders[e]; ok {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+func normalizeEncoding(e string) string {
+	e = strings.ToLower(strings.TrimSpace(e))
+	if e == "brotli" {
+		return "br"
+	}
+	return e
+}
+
+// negotiateEncoding 按 Accept-Encoding 的 q 值在 offers 中选出编码，无可用编码时返回空串
+func negotiateEncoding(accept string, offers []string) string {
+	q := map[string]float64{}
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = normalizeEncoding(name)
+		if name == "" {
+			continue
+		}
+		weight := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				weight = f
+			}
+		}
+		q[name] = weight
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		weight, ok := q[offer]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > bestQ {
+			best, bestQ = offer, weight
+		}
+	}
+	return best
+}
+
+// Compress 按 Accept-Encoding 压缩响应，未配置 COMPRESS 时直接透传
+func Compress(next http.Handler) http.Handler {
+	if len(compressEncodings) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), compressEncodings)
+		if encoding == "" || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, head: r.Method == http.MethodHead, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// compressWriter 先缓冲到 compressMinSize 再决定是否压缩
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	head        bool
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	enc         encoder
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified || w.head {
+		_ = w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) >= compressMinSize {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// decide 写出响应头和已缓冲的数据，此后的写入直接压缩或透传
+func (w *compressWriter) decide(compress bool) error {
+	if w.decided {
+		return nil
+	}
+	w.decided = true
+	h := w.Header()
+	if compress && w.compressible(h) {
+		h.Set("Content-Encoding", w.encoding)
+		// 压缩后与原始内容是不同的表示，强 ETag 改为弱 ETag，避免缓存把两者当成同一份（与 nginx gzip 一致）
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		w.enc = encoders[w.encoding](w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(w.buf)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+	w.buf = nil
+	return err
+}
+
+// compressible 已压缩、Range、固定长度（如 /bytes）或二进制内容不压缩：
+// Range 偏移按原始字节计算，Content-Length / Accept-Ranges 是处理函数要保留的语义
+func (w *compressWriter) compressible(h http.Header) bool {
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" ||
+		h.Get("Content-Length") != "" || h.Get("Accept-Ranges") != "" ||
+		strings.HasPrefix(h.Get("Content-Type"), "application/octet-stream") {
+		return false
+	}
+	return w.status != http.StatusNoContent && w.status != http.StatusNotModified && !w.head
+}
+
+// Flush 流式响应无法预知长度，视为超过阈值直接开始压缩
+func (w *compressWriter) Flush() {
+	_ = w.decide(true)
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack 供需要接管连接的处理函数使用，接管后不再压缩
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close 处理函数返回后调用：不足阈值的响应原样写出，否则结束压缩流
+func (w *compressWriter) Close() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+	}
+}
+
+// Compressed 无论 Accept-Encoding 如何都按路径指定的编码返回，如 /compressed/gzip
+func Compressed(w http.ResponseWriter, r *http.Request) {
+	encoding := normalizeEncoding(PathParam(r, "/compressed/"))
+	newEncoder, ok := encoders[encoding]
+	if !ok {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("unsupported encoding: %q, available: gzip deflate br zstd", encoding)})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Encoding", encoding)
+	w.WriteHeader(http.StatusOK)
+	enc := newEncoder(w)
+	defer enc.Close()
+	_ = json.NewEncoder(enc).Encode(Resp{Code: 0, Msg: encoding, Data: map[string]interface{}{
+		"method":          r.Method,
+		"accept_encoding": r.Header.Get("Accept-Encoding"),
+		"pod":             PodName(),
+	}})
+}
@@ -0,0 +1,39 @@
+package common
+
+import "testing"
+
+func TestNegotiateEncoding(t *testing.T) {
+	offers := []string{"br", "zstd", "gzip"}
+	tests := []struct {
+		accept string
+		offers []string
+		want   string
+	}{
+		{"", offers, ""},
+		{"identity", offers, ""},
+		{"gzip", offers, "gzip"},
+		{"GZIP", offers, "gzip"},
+		{"brotli", offers, "br"},
+		// q 值相同时按服务端偏好
+		{"gzip, br", offers, "br"},
+		{"gzip;q=1.0, br;q=0.5", offers, "gzip"},
+		{" gzip ; q=0.8 , zstd;q=0.9", offers, "zstd"},
+		// q=0 表示不接受
+		{"br;q=0", offers, ""},
+		{"br;q=0, gzip;q=0.1", offers, "gzip"},
+		// 通配符只作用于未显式列出的编码
+		{"*", offers, "br"},
+		{"*;q=0.5, gzip", offers, "gzip"},
+		{"br;q=0, *", offers, "zstd"},
+		{"gzip;q=0, *", []string{"gzip"}, ""},
+		// 非法 q 值按 1 处理
+		{"gzip;q=abc", offers, "gzip"},
+		{"deflate", offers, ""},
+		{"gzip", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.accept, tt.offers); got != tt.want {
+			t.Errorf("negotiateEncoding(%q, %v) = %q, want %q", tt.accept, tt.offers, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------- 真实 CPU 负载（让压测与 profile 更接近真实服务，便于比较四个框架） ----------
+
+// cpuLoadMax 单次请求的最长执行时间
+const cpuLoadMax = time.Minute
+
+// workload 一种负载，run 执行一次迭代并返回处理的字节数与校验值
+type workload struct {
+	desc string
+	run  func(i uint64) (int64, uint64)
+}
+
+// loadOrder 演示用的订单文档
+type loadOrder struct {
+	ID       string            `json:"id"`
+	Customer string            `json:"customer"`
+	Tags     []string          `json:"tags"`
+	Attrs    map[string]string `json:"attrs"`
+	Items    []loadItem        `json:"items"`
+	Total    float64           `json:"total"`
+	Paid     bool              `json:"paid"`
+}
+
+type loadItem struct {
+	SKU   string  `json:"sku"`
+	Name  string  `json:"name"`
+	Qty   int     `json:"qty"`
+	Price float64 `json:"price"`
+}
+
+var (
+	loadOnce  sync.Once
+	loadBlock []byte // 4 KiB 伪随机数据
+	loadText  []byte // 16 KiB 类日志文本
+	loadOrd   loadOrder
+	loadInts  []int
+	loadRegex []*regexp.Regexp
+)
+
+// loadFixtures 准备各负载共用的只读输入
+func loadFixtures() {
+	loadOnce.Do(func() {
+		p := &payload{size: 4096, seed: 1}
+		loadBlock = make([]byte, 4096)
+		_, _ = p.ReadAt(loadBlock, 0)
+
+		var sb strings.Builder
+		for i := 0; sb.Len() < 16<<10; i++ {
+			fmt.Fprintf(&sb, "2026-01-02T15:04:%02d.%03dZ INFO 10.0.%d.%d GET /api/v1/orders/%d?user=user%d@example.com 200 %dms \"Mozilla/5.0\"\n",
+				i%60, i%1000, i%256, (i*7)%256, 100000+i, i%97, i%500)
+		}
+		loadText = []byte(sb.String())
+
+		loadOrd = loadOrder{ID: "ord-000001", Customer: "customer@example.com", Tags: []string{"vip", "express", "gift"},
+			Attrs: map[string]string{"channel": "web", "region": "cn-east", "coupon": "SPRING"}, Paid: true}
+		for i := 0; i < 20; i++ {
+			it := loadItem{SKU: "SKU-" + strconv.Itoa(1000+i), Name: "Item number " + strconv.Itoa(i), Qty: i%5 + 1, Price: float64(i)*1.25 + 9.99}
+			loadOrd.Items = append(loadOrd.Items, it)
+			loadOrd.Total += float64(it.Qty) * it.Price
+		}
+
+		loadInts = make([]int, 1000)
+		for i := range loadInts {
+			loadInts[i] = int(splitmix64(uint64(i)) >> 1)
+		}
+
+		for _, expr := range []string{
+			`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`,
+			`[\w.+-]+@[\w-]+\.[\w.]+`,
+			`/api/v\d+/(\w+)/(\d+)`,
+			`\s(\d{3})\s(\d+)ms`,
+		} {
+			loadRegex = append(loadRegex, regexp.MustCompile(expr))
+		}
+	})
+}
+
+var workloads = map[string]workload{
+	"hash": {"SHA-256 of a 4 KiB block", func(i uint64) (int64, uint64) {
+		buf := make([]byte, len(loadBlock))
+		copy(buf, loadBlock)
+		binary.LittleEndian.PutUint64(buf, i)
+		sum := sha256.Sum256(buf)
+		return int64(len(buf)), binary.LittleEndian.Uint64(sum[:])
+	}},
+	"json": {"encode and decode an order with 20 items", func(i uint64) (int64, uint64) {
+		o := loadOrd
+		o.ID = "ord-" + strconv.FormatUint(i, 10)
+		data, _ := json.Marshal(o)
+		var back loadOrder
+		_ = json.Unmarshal(data, &back)
+		return int64(len(data)), uint64(len(back.Items)) + uint64(back.Total)
+	}},
+	"compress": {"gzip 16 KiB of log text", func(i uint64) (int64, uint64) {
+		var out bytes.Buffer
+		zw := gzip.NewWriter(&out)
+		_, _ = zw.Write(loadText)
+		_ = zw.Close()
+		return int64(len(loadText)), uint64(out.Len()) + i
+	}},
+	"regex": {"match IP, email, URL and status patterns over 16 KiB of log text", func(i uint64) (int64, uint64) {
+		var n uint64
+		for _, re := range loadRegex {
+			n += uint64(len(re.FindAllIndex(loadText, -1)))
+		}
+		return int64(len(loadText)) * int64(len(loadRegex)), n + i
+	}},
+	"sort": {"sort 1000 integers", func(i uint64) (int64, uint64) {
+		a := make([]int, len(loadInts))
+		copy(a, loadInts)
+		a[0] = int(i)
+		sort.Ints(a)
+		return int64(len(a) * 8), uint64(a[len(a)/2])
+	}},
+	"alloc": {"build 1000 small objects in a map", func(i uint64) (int64, uint64) {
+		type obj struct {
+			id   uint64
+			name string
+			tags []string
+		}
+		m := make(map[string]*obj)
+		for j := uint64(0); j < 1000; j++ {
+			k := strconv.FormatUint(i*1000+j, 36)
+			m[k] = &obj{id: j, name: "obj-" + k, tags: []string{k, "t"}}
+		}
+		return 1000 * 64, uint64(len(m))
+	}},
+}
+
+// CPULoad 按类型执行真实的 CPU 负载，按迭代次数或目标时长计量，返回完成的工作量
+//
+//	/cpu/{kind}?iterations=1000   执行固定次数
+//	/cpu/{kind}?ms=200            持续执行约 200ms（默认 100ms）
+//	kind  hash / json / compress / regex / sort / alloc
+func CPULoad(w http.ResponseWriter, r *http.Request) {
+	kind := PathParam(r, "/cpu/")
+	wl, ok := workloads[kind]
+	if !ok {
+		kinds := make([]string, 0, len(workloads))
+		for k := range workloads {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("unknown kind: %q, available: %s", kind, strings.Join(kinds, " "))})
+		return
+	}
+	iterations := QueryInt(r, "iterations", 0)
+	target := time.Duration(QueryInt(r, "ms", 0)) * time.Millisecond
+	if iterations <= 0 && target <= 0 {
+		target = 100 * time.Millisecond
+	}
+	if iterations > 0 {
+		target = 0
+	}
+	target = min(target, cpuLoadMax)
+	loadFixtures()
+
+	ctx := r.Context()
+	start := time.Now()
+	deadline := start.Add(cpuLoadMax)
+	if target > 0 {
+		deadline = start.Add(target)
+	}
+	var n, processed int64
+	var checksum uint64
+	for iterations <= 0 || n < int64(iterations) {
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			break
+		}
+		b, c := wl.run(uint64(n))
+		processed += b
+		checksum += c
+		n++
+	}
+	elapsed := time.Since(start)
+
+	secs := max(elapsed.Seconds(), 1e-9)
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: map[string]interface{}{
+		"kind":               kind,
+		"description":        wl.desc,
+		"iterations":         n,
+		"bytes":              processed,
+		"duration_ms":        float64(elapsed.Microseconds()) / 1000,
+		"iterations_per_sec": int64(float64(n) / secs),
+		"mb_per_sec":         float64(processed) / secs / (1 << 20),
+		"checksum":           strconv.FormatUint(checksum, 16),
+		"pod":                PodName(),
+	}})
+}
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// ---------- 回显（验证 proxy-rewrite、body-transformer 等改写结果） ----------
+
+// echoMaxBody 回显请求体的上限（字节），由 ECHO_MAX_BODY 指定，默认 1 MiB
+var echoMaxBody = int64(EnvInt("ECHO_MAX_BODY", 1<<20))
+
+// EchoData 按原样整理请求的方法、地址、头、协议、TLS 与请求体
+//
+// 请求体按 Content-Type 解析：JSON 解析为对象，表单与 multipart 解析为字段和文件元数据，
+// 文本原样返回，二进制以 base64 返回。超过 ECHO_MAX_BODY 时返回错误
+func EchoData(r *http.Request) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"method":            r.Method,
+		"host":              r.Host,
+		"uri":               r.RequestURI,
+		"path":              r.URL.Path,
+		"query":             r.URL.Query(),
+		"headers":           r.Header,
+		"proto":             r.Proto,
+		"remote_addr":       r.RemoteAddr,
+		"content_length":    r.ContentLength,
+		"transfer_encoding": r.TransferEncoding,
+		"tls":               TLSInfo(r),
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, echoMaxBody))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, fmt.Errorf("request body exceeds ECHO_MAX_BODY %d", tooLarge.Limit)
+	}
+	if err != nil {
+		data["body_error"] = err.Error()
+	}
+	// Trailer 只有在请求体读完后才可用
+	if len(r.Trailer) > 0 {
+		data["trailers"] = r.Trailer
+	}
+	data["body_size"] = len(body)
+	if len(body) > 0 {
+		for k, v := range decodeBody(r.Header.Get("Content-Type"), body) {
+			data[k] = v
+		}
+	}
+	return data, nil
+}
+
+// decodeBody 按 Content-Type 解码请求体
+func decodeBody(contentType string, body []byte) map[string]interface{} {
+	out := map[string]interface{}{}
+	mt, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		// UseNumber 保留数字原文，避免大整数经 float64 丢失精度
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v interface{}
+		err := dec.Decode(&v)
+		if _, tail := dec.Token(); err == nil && tail != io.EOF {
+			err = errors.New("invalid data after top-level JSON value")
+		}
+		if err != nil {
+			out["json_error"] = err.Error()
+		} else {
+			out["json"] = v
+		}
+	case mt == "application/x-www-form-urlencoded":
+		if form, err := url.ParseQuery(string(body)); err != nil {
+			out["form_error"] = err.Error()
+		} else {
+			out["form"] = form
+		}
+	case strings.HasPrefix(mt, "multipart/"):
+		form, files, err := decodeMultipart(body, params["boundary"])
+		out["form"] = form
+		out["files"] = files
+		if err != nil {
+			out["form_error"] = err.Error()
+		}
+	}
+
+	if utf8.Valid(body) {
+		out["body"] = string(body)
+		out["body_encoding"] = "utf8"
+	} else {
+		out["body"] = base64.StdEncoding.EncodeToString(body)
+		out["body_encoding"] = "base64"
+	}
+	return out
+}
+
+// decodeMultipart 解析 multipart 字段，文件只返回元数据
+func decodeMultipart(body []byte, boundary string) (url.Values, []map[string]interface{}, error) {
+	form := url.Values{}
+	files := []map[string]interface{}{}
+	if boundary == "" {
+		return form, files, errors.New("missing multipart boundary")
+	}
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return form, files, nil
+		}
+		if err != nil {
+			return form, files, err
+		}
+		if part.FileName() == "" {
+			v, err := io.ReadAll(part)
+			if err != nil {
+				return form, files, err
+			}
+			form.Add(part.FormName(), string(v))
+			continue
+		}
+		sum := sha256.New()
+		n, err := io.Copy(sum, part)
+		if err != nil {
+			return form, files, err
+		}
+		files = append(files, map[string]interface{}{
+			"field":        part.FormName(),
+			"filename":     part.FileName(),
+			"content_type": part.Header.Get("Content-Type"),
+			"size":         n,
+			"sha256":       hex.EncodeToString(sum.Sum(nil)),
+			"headers":      part.Header,
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ---------- 前 N 次失败（验证网关 retries 与 retry_timeout） ----------
+
+// flakyAttempt 单个 key 的尝试次数，超过 expires 后重新计数
+type flakyAttempt struct {
+	count   int
+	expires time.Time
+}
+
+var flaky = struct {
+	sync.Mutex
+	m map[string]*flakyAttempt
+}{m: make(map[string]*flakyAttempt)}
+
+// Flaky 同一 key 的前 fail 次请求返回 status，之后成功，计数在 ttl 后过期
+//
+//	/flaky?key=abc&fail=2&status=503&ttl=60
+//	key     计数用的 key，默认 default
+//	fail    失败次数，默认 2
+//	status  失败时的状态码，400-599，默认 503
+//	ttl     计数自最后一次请求起的保留时长，默认 60s
+func Flaky(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		key = "default"
+	}
+	fail := QueryInt(r, "fail", 2)
+	status := QueryInt(r, "status", http.StatusServiceUnavailable)
+	ttl := queryDuration(r, "ttl", time.Minute)
+	// 1xx 会被 net/http 当作中间响应，最终仍返回 200，因此失败状态码只允许 4xx / 5xx
+	if status < 400 || status > 599 {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("invalid status: %d, must be 400-599", status)})
+		return
+	}
+
+	now := time.Now()
+	flaky.Lock()
+	for k, a := range flaky.m {
+		if now.After(a.expires) {
+			delete(flaky.m, k)
+		}
+	}
+	a, ok := flaky.m[key]
+	if !ok {
+		a = &flakyAttempt{}
+		flaky.m[key] = a
+	}
+	a.count++
+	a.expires = now.Add(ttl)
+	attempt := a.count
+	flaky.Unlock()
+
+	w.Header().Set("X-Attempt", strconv.Itoa(attempt))
+	data := map[string]interface{}{
+		"key":     key,
+		"attempt": attempt,
+		"fail":    fail,
+		"ttl":     ttl.String(),
+		"pod":     PodName(),
+	}
+	if attempt <= fail {
+		WriteJSON(w, status, Resp{Code: 1, Msg: fmt.Sprintf("attempt %d of %d failed", attempt, fail), Data: data})
+		return
+	}
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Msg: fmt.Sprintf("succeeded on attempt %d", attempt), Data: data})
+}
@@ -0,0 +1,216 @@
+package common
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ---------- 可切换的上游健康状态（验证 APISIX 主动 / 被动健康检查阈值） ----------
+
+// healthModes /admin/health 可设置的模式
+//
+//	healthy    200
+//	unhealthy  返回 status（默认 503）
+//	slow       等待 delay（默认 5s）后返回 200，用于触发检查超时
+//	refuse     直接 RST 连接；HEALTH_PORT 监听会被关闭，TCP 检查得到 connection refused
+//	flap       健康 up（默认 10s）、不健康 down（默认 10s）交替
+var healthModes = map[string]bool{"healthy": true, "unhealthy": true, "slow": true, "refuse": true, "flap": true}
+
+var healthChecks = NewCounter("health_upstream_checks_total", "Total requests to /health/upstream.")
+
+var health = struct {
+	sync.Mutex
+	mode      string
+	status    int
+	delay     time.Duration
+	up, down  time.Duration
+	since     time.Time
+	checks    int64
+	lastCheck time.Time
+	lastFrom  string
+	// port 为 HEALTH_PORT 监听地址，ln 为当前监听，refuse 模式下为 nil
+	port string
+	ln   net.Listener
+}{mode: "healthy", status: http.StatusServiceUnavailable, delay: 5 * time.Second, up: 10 * time.Second, down: 10 * time.Second, since: time.Now()}
+
+// initialHealthMode 启动时的模式，由 HEALTH_MODE 指定，默认 healthy，非法时拒绝启动；由 ListenAndServe 调用
+func initialHealthMode() string {
+	m := os.Getenv("HEALTH_MODE")
+	if m == "" {
+		return "healthy"
+	}
+	if !healthModes[m] {
+		log.Fatalf("Invalid HEALTH_MODE: %s, available: healthy unhealthy slow refuse flap", m)
+	}
+	return m
+}
+
+// healthView 当前状态，调用方需持有锁
+func healthView() map[string]interface{} {
+	v := map[string]interface{}{
+		"mode":   health.mode,
+		"since":  health.since.Format(time.RFC3339Nano),
+		"checks": health.checks,
+		"pod":    PodName(),
+	}
+	switch health.mode {
+	case "unhealthy":
+		v["status"] = health.status
+	case "slow":
+		v["delay"] = health.delay.String()
+	case "flap":
+		v["status"] = health.status
+		v["up"] = health.up.String()
+		v["down"] = health.down.String()
+		v["healthy_now"] = flapHealthy(time.Now())
+	}
+	if !health.lastCheck.IsZero() {
+		v["last_check"] = health.lastCheck.Format(time.RFC3339Nano)
+		v["last_check_from"] = health.lastFrom
+	}
+	if health.port != "" {
+		v["health_port"] = health.port
+		v["health_port_open"] = health.ln != nil
+	}
+	return v
+}
+
+// flapHealthy flap 模式下当前是否处于健康阶段，调用方需持有锁
+func flapHealthy(now time.Time) bool {
+	cycle := health.up + health.down
+	if cycle <= 0 {
+		return true
+	}
+	return now.Sub(health.since)%cycle < health.up
+}
+
+// HealthUpstream 供网关健康检查使用，结果由 /admin/health 控制
+func HealthUpstream(w http.ResponseWriter, r *http.Request) {
+	healthChecks.Inc()
+	now := time.Now()
+	health.Lock()
+	health.checks++
+	health.lastCheck = now
+	health.lastFrom = clientIP(r)
+	mode, status, delay := health.mode, http.StatusOK, health.delay
+	switch mode {
+	case "unhealthy":
+		status = health.status
+	case "flap":
+		if !flapHealthy(now) {
+			status = health.status
+		}
+	}
+	health.Unlock()
+
+	switch mode {
+	case "refuse":
+		resetConn(w)
+		return
+	case "slow":
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	msg := "healthy"
+	if status != http.StatusOK {
+		msg = "unhealthy"
+	}
+	WriteJSON(w, status, Resp{Code: 0, Msg: msg, Data: map[string]interface{}{"mode": mode, "pod": PodName()}})
+}
+
+// resetConn 接管连接并以 RST 关闭；HTTP/2、HTTP/3 无法接管，返回 503
+func resetConn(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		WriteJSON(w, http.StatusServiceUnavailable, Resp{Code: 1, Msg: "refuse: " + err.Error()})
+		return
+	}
+	rstClose(conn)
+}
+
+// HealthAdmin 查看或切换 /health/upstream 的状态
+//
+//	GET  /admin/health
+//	POST /admin/health?mode=unhealthy&status=500
+//	POST /admin/health?mode=slow&delay=3s
+//	POST /admin/health?mode=flap&up=20s&down=10s&status=502
+//	POST /admin/health?mode=refuse
+func HealthAdmin(w http.ResponseWriter, r *http.Request) {
+	if !adminAllowed(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		mode := r.URL.Query().Get("mode")
+		if !healthModes[mode] {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "invalid mode: " + mode + ", available: healthy unhealthy slow refuse flap"})
+			return
+		}
+		status := QueryInt(r, "status", http.StatusServiceUnavailable)
+		// 1xx 会被 net/http 当作中间响应，健康检查最终仍看到 200
+		if status < 400 || status > 599 {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("invalid status: %d, must be 400-599", status)})
+			return
+		}
+		health.Lock()
+		health.mode = mode
+		health.status = status
+		health.delay = queryDuration(r, "delay", 5*time.Second)
+		health.up = queryDuration(r, "up", 10*time.Second)
+		health.down = queryDuration(r, "down", 10*time.Second)
+		health.since = time.Now()
+		syncHealthPort()
+		health.Unlock()
+		log.Printf("upstream health set to %s", mode)
+	}
+
+	health.Lock()
+	v := healthView()
+	health.Unlock()
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: v})
+}
+
+// serveHealthPort 启动只提供 /health/upstream 的独立监听，refuse 模式下关闭监听
+func serveHealthPort(addr string) {
+	health.Lock()
+	defer health.Unlock()
+	health.port = addr
+	syncHealthPort()
+}
+
+// syncHealthPort 按当前模式打开或关闭 HEALTH_PORT 监听，调用方需持有锁
+func syncHealthPort() {
+	if health.port == "" {
+		return
+	}
+	if health.mode == "refuse" {
+		if health.ln != nil {
+			_ = health.ln.Close()
+			health.ln = nil
+		}
+		return
+	}
+	if health.ln != nil {
+		return
+	}
+	ln, err := net.Listen("tcp", health.port)
+	if err != nil {
+		log.Printf("Failed to listen HEALTH_PORT %s: %v", health.port, err)
+		return
+	}
+	health.ln = ln
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health/upstream", HealthUpstream)
+	go func() {
+		_ = http.Serve(ln, mux)
+	}()
+	log.Printf("health check listening on %s", health.port)
+}
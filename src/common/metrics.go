@@ -0,0 +1,67 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ---------- 指标（Prometheus 文本格式） ----------
+
+// Metric 单个计数器或仪表盘
+type Metric struct {
+	name string
+	help string
+	kind string
+	v    atomic.Int64
+}
+
+func (m *Metric) Inc()         { m.v.Add(1) }
+func (m *Metric) Dec()         { m.v.Add(-1) }
+func (m *Metric) Add(n int64)  { m.v.Add(n) }
+func (m *Metric) Set(n int64)  { m.v.Store(n) }
+func (m *Metric) Value() int64 { return m.v.Load() }
+
+var registry = struct {
+	sync.Mutex
+	m map[string]*Metric
+}{m: make(map[string]*Metric)}
+
+func register(name, help, kind string) *Metric {
+	registry.Lock()
+	defer registry.Unlock()
+	if m, ok := registry.m[name]; ok {
+		return m
+	}
+	m := &Metric{name: name, help: help, kind: kind}
+	registry.m[name] = m
+	return m
+}
+
+// NewCounter 注册只增不减的计数器，同名重复注册返回同一个实例
+func NewCounter(name, help string) *Metric {
+	return register(name, help, "counter")
+}
+
+// NewGauge 注册可增可减的仪表盘，同名重复注册返回同一个实例
+func NewGauge(name, help string) *Metric {
+	return register(name, help, "gauge")
+}
+
+// Metrics 以 Prometheus 文本格式输出所有指标
+func Metrics(w http.ResponseWriter, _ *http.Request) {
+	registry.Lock()
+	list := make([]*Metric, 0, len(registry.m))
+	for _, m := range registry.m {
+		list = append(list, m)
+	}
+	registry.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, m := range list {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", m.name, m.help, m.name, m.kind, m.name, m.Value())
+	}
+}
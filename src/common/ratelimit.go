@@ -0,0 +1,158 @@
+package common
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------- 令牌桶限流（与 APISIX limit-req / limit-count 对照） ----------
+
+var rateLimited = NewCounter("rate_limited_total", "Total requests rejected by the rate limiter.")
+
+// bucket 单个 key 的令牌桶
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter 令牌桶限流器，每秒补充 rate 个令牌，最多 burst 个
+type limiter struct {
+	sync.Mutex
+	rate    float64
+	burst   float64
+	key     string
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// parseRate 解析 10、10/s、600/m、1000/h
+func parseRate(s string) (float64, error) {
+	n, unit, _ := strings.Cut(strings.TrimSpace(s), "/")
+	v, err := strconv.ParseFloat(n, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid rate: %q", s)
+	}
+	switch unit {
+	case "", "s":
+	case "m":
+		v /= 60
+	case "h":
+		v /= 3600
+	default:
+		return 0, fmt.Errorf("invalid rate unit: %q", s)
+	}
+	return v, nil
+}
+
+// newLimiter 按环境变量创建限流器，未开启时返回 nil
+//
+//	RATE_LIMIT=10/s            补充速率，支持 /s /m /h
+//	RATE_LIMIT_BURST=20        桶容量，默认等于每秒速率（至少 1）
+//	RATE_LIMIT_KEY=ip          限流维度：ip（默认，按 clientIP）/ header:X-Api-Key / route（按一级路径，如 /bytes）
+func newLimiter() *limiter {
+	v := os.Getenv("RATE_LIMIT")
+	if v == "" {
+		return nil
+	}
+	rate, err := parseRate(v)
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT: %v", err)
+	}
+	if rate <= 0 {
+		return nil
+	}
+	burst := float64(EnvInt("RATE_LIMIT_BURST", int(math.Max(math.Ceil(rate), 1))))
+	key := os.Getenv("RATE_LIMIT_KEY")
+	if key == "" {
+		key = "ip"
+	}
+	if key != "ip" && key != "route" && !strings.HasPrefix(key, "header:") {
+		log.Fatalf("Invalid RATE_LIMIT_KEY: %s, available: ip header:<name> route", key)
+	}
+	log.Printf("rate limit %g/s, burst %g, key %s", rate, burst, key)
+	return &limiter{rate: rate, burst: burst, key: key, buckets: make(map[string]*bucket), swept: time.Now()}
+}
+
+// keyOf 请求对应的限流 key，按 header 限流但请求未带该头时退回按 IP
+func (l *limiter) keyOf(r *http.Request) string {
+	switch {
+	case l.key == "route":
+		// 按一级路径分桶，/bytes/1 与 /bytes/2 共用 /bytes 的桶，改变路径参数不能绕过限流
+		seg, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		return "/" + seg
+	case strings.HasPrefix(l.key, "header:"):
+		if v := r.Header.Get(strings.TrimPrefix(l.key, "header:")); v != "" {
+			return v
+		}
+	}
+	return clientIP(r)
+}
+
+// take 取一个令牌，返回是否放行与取后的剩余令牌数
+func (l *limiter) take(key string, now time.Time) (bool, float64) {
+	l.Lock()
+	defer l.Unlock()
+
+	// 定期清理已补满的桶
+	if now.Sub(l.swept) > time.Minute {
+		for k, b := range l.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, b.tokens
+	}
+	b.tokens--
+	return true, b.tokens
+}
+
+// rateLimit 令牌桶限流，超出时返回 429，并带上 Retry-After 与 RateLimit-* 头
+func rateLimit(next http.Handler) http.Handler {
+	l := newLimiter()
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ok, tokens := l.take(l.keyOf(r), time.Now())
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(int(l.burst)))
+		h.Set("RateLimit-Remaining", strconv.Itoa(int(tokens)))
+		h.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil((l.burst-tokens)/l.rate))))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", int(l.burst), int(math.Ceil(l.burst/l.rate))))
+		if ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rateLimited.Inc()
+		retry := int(math.Ceil((1 - tokens) / l.rate))
+		h.Set("Retry-After", strconv.Itoa(retry))
+		WriteJSON(w, http.StatusTooManyRequests, Resp{Code: 1, Msg: fmt.Sprintf("rate limited, retry after %ds", retry), Data: map[string]interface{}{
+			"key": l.key,
+			"pod": PodName(),
+		}})
+	})
+}
@@ -0,0 +1,107 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiterTake(t *testing.T) {
+	t0 := time.Now()
+	at := func(d time.Duration) time.Time { return t0.Add(d) }
+	type step struct {
+		key    string
+		now    time.Time
+		ok     bool
+		tokens float64
+	}
+	tests := []struct {
+		name  string
+		rate  float64
+		burst float64
+		steps []step
+	}{
+		{
+			name: "burst then refill", rate: 1, burst: 2,
+			steps: []step{
+				{"a", at(0), true, 1},
+				{"a", at(0), true, 0},
+				{"a", at(0), false, 0},
+				{"a", at(500 * time.Millisecond), false, 0.5},
+				{"a", at(time.Second), true, 0},
+				// 长时间空闲后最多补满到 burst
+				{"a", at(10 * time.Second), true, 1},
+			},
+		},
+		{
+			name: "keys are independent", rate: 1, burst: 1,
+			steps: []step{
+				{"a", at(0), true, 0},
+				{"a", at(0), false, 0},
+				{"b", at(0), true, 0},
+			},
+		},
+		{
+			name: "per-minute rate", rate: 1.0 / 60, burst: 1,
+			steps: []step{
+				{"a", at(0), true, 0},
+				{"a", at(30 * time.Second), false, 0.5},
+				{"a", at(time.Minute), true, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		l := &limiter{rate: tt.rate, burst: tt.burst, buckets: map[string]*bucket{}, swept: t0}
+		for i, s := range tt.steps {
+			ok, tokens := l.take(s.key, s.now)
+			if ok != s.ok || tokens < s.tokens-1e-9 || tokens > s.tokens+1e-9 {
+				t.Errorf("%s: step %d take(%q) = %v, %g, want %v, %g", tt.name, i, s.key, ok, tokens, s.ok, s.tokens)
+			}
+		}
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	t0 := time.Now()
+	l := &limiter{rate: 1, burst: 2, buckets: map[string]*bucket{}, swept: t0}
+	l.take("idle", t0)
+	l.take("busy", t0)
+	l.take("busy", t0)
+	// 一分钟后 idle 已补满被清理，busy 在清理时同样已补满
+	l.take("new", t0.Add(2*time.Minute))
+	if _, ok := l.buckets["idle"]; ok {
+		t.Errorf("idle bucket not swept")
+	}
+	if len(l.buckets) != 1 {
+		t.Errorf("buckets = %d, want 1", len(l.buckets))
+	}
+}
+
+func TestLimiterKeyOf(t *testing.T) {
+	tests := []struct {
+		key    string
+		path   string
+		header string
+		want   string
+	}{
+		{"route", "/bytes/1", "", "/bytes"},
+		{"route", "/bytes/2?x=1", "", "/bytes"},
+		{"route", "/ping", "", "/ping"},
+		{"route", "/", "", "/"},
+		{"header:X-Api-Key", "/echo", "k1", "k1"},
+		// 未带该头时按 IP
+		{"header:X-Api-Key", "/echo", "", "192.0.2.1"},
+		{"ip", "/echo", "k1", "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			r.Header.Set("X-Api-Key", tt.header)
+		}
+		l := &limiter{key: tt.key}
+		if got := l.keyOf(r); got != tt.want {
+			t.Errorf("keyOf(%s, %s) = %q, want %q", tt.key, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package common
+
+import (
+	"hash/fnv"
+	"html/template"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ---------- 实例标识与根路径（灰度 / traffic-split 演示） ----------
+
+// Route 已注册的路由，Method 为 "*" 表示不限方法
+type Route struct {
+	Method string
+	Path   string
+}
+
+// identity 为每个响应加上实例标识头
+func identity(framework string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		if v := PodName(); v != "" {
+			h.Set("X-Pod-Name", v)
+		}
+		if v := Version(); v != "" {
+			h.Set("X-Version", v)
+		}
+		h.Set("X-Framework", framework)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// palette 按版本取色，同一版本在所有实例上颜色一致
+var palette = []string{"#2f80ed", "#27ae60", "#eb5757", "#f2994a", "#9b51e0", "#00a3a3", "#d6336c", "#6c757d"}
+
+// versionColor 优先使用 COLOR 环境变量
+func versionColor() string {
+	if c := os.Getenv("COLOR"); c != "" {
+		return c
+	}
+	f := fnv.New32a()
+	f.Write([]byte(Version()))
+	return palette[f.Sum32()%uint32(len(palette))]
+}
+
+var rootPage = template.Must(template.New("root").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>demo-go-tiny {{.Version}}</title>
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+<style>
+body{margin:0;height:100vh;display:flex;align-items:center;justify-content:center;background:{{.Color}};color:#fff;font-family:sans-serif}
+.card{text-align:center}
+h1{font-size:4em;margin:0}
+table{margin:1em auto;font-size:1.4em}
+td{padding:.2em .8em;text-align:left}
+</style>
+</head>
+<body>
+<div class="card">
+<h1>{{if .Version}}{{.Version}}{{else}}no version{{end}}</h1>
+<table>
+<tr><td>Pod</td><td>{{.Pod}}</td></tr>
+<tr><td>Node</td><td>{{.Node}}</td></tr>
+<tr><td>Framework</td><td>{{.Framework}}</td></tr>
+<tr><td>Client</td><td>{{.Client}}</td></tr>
+</table>
+</div>
+</body>
+</html>
+`))
+
+// Root 浏览器访问返回按版本着色的 HTML 页面，其余返回实际注册的路由列表
+//
+//	/?refresh=1  HTML 页面每秒自动刷新，便于观察流量拆分
+func Root(w http.ResponseWriter, r *http.Request, framework string, routes []Route) {
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = rootPage.Execute(w, map[string]interface{}{
+			"Version":   Version(),
+			"Pod":       PodName(),
+			"Node":      os.Getenv("NODE_NAME"),
+			"Framework": framework,
+			"Client":    clientIP(r),
+			"Color":     template.CSS(versionColor()),
+			"Refresh":   QueryInt(r, "refresh", 0),
+		})
+		return
+	}
+
+	// 同一路径的多个方法合并展示
+	methods := map[string][]string{}
+	for _, rt := range routes {
+		methods[rt.Path] = append(methods[rt.Path], rt.Method)
+	}
+	paths := make([]string, 0, len(methods))
+	for p := range methods {
+		paths = append(paths, p)
+		sort.Strings(methods[p])
+	}
+	sort.Strings(paths)
+	list := make([]map[string]interface{}, 0, len(paths))
+	for _, p := range paths {
+		list = append(list, map[string]interface{}{"path": p, "methods": methods[p]})
+	}
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"framework": framework,
+		"routes":    list,
+	})
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/quic-go/quic-go/http3"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Wrap 为各框架的 Handler 套上公共中间件，framework 为 -c 指定的框架名
+func Wrap(framework string, h http.Handler) http.Handler {
+	h = concurrencyLimit(h)
+	h = rateLimit(h)
+	// capture 在 Compress 内侧，录到的是压缩前的响应体
+	h = capture(h)
+	h = Compress(h)
+	h = throttle(h)
+	h = identity(framework, h)
+	return h
+}
+
+// ListenAndServe 各框架统一的启动入口
+//
+//	H2C=true                   主端口同时接受 h2c（prior knowledge 与 Upgrade: h2c）
+//	TLS_PORT=8443              额外启动 HTTPS 监听，证书与客户端认证见 tlsConfig
+//	TLS_ALPN=h2,http/1.1       HTTPS 通过 ALPN 协商的协议，去掉 h2 即只提供 HTTP/1.1
+//	H3_PORT=8443               额外启动 HTTP/3（UDP）监听，与 HTTPS 共用证书，并在 TCP 监听的响应中通过 Alt-Svc 声明
+//	TCP_PORT / UDP_PORT        额外启动原始 TCP / UDP 回显监听，模式见 streamMode
+//	HEALTH_PORT=8081           额外启动只提供 /health/upstream 的监听，refuse 模式下关闭
+func ListenAndServe(framework, addr string, h http.Handler) error {
+	// 环境变量在启动时校验，不放在包初始化中，避免影响 go test 与命令行参数解析
+	streamMode = envStreamMode()
+	health.mode = initialHealthMode()
+
+	h = Wrap(framework, h)
+
+	// 证书在启动时准备好，避免各监听并发初始化
+	if os.Getenv("TLS_PORT") != "" || os.Getenv("H3_PORT") != "" {
+		tlsConfig()
+	}
+
+	if port := os.Getenv("H3_PORT"); port != "" {
+		// HTTP/3 监听使用未加 Alt-Svc 的 h，Alt-Svc 只加在 TCP 监听上
+		go func(h http.Handler) {
+			log.Fatalf("HTTP/3 server failed: %v", serveH3(":"+port, h))
+		}(h)
+		h = altSvc(h, port)
+	}
+
+	if port := os.Getenv("TLS_PORT"); port != "" {
+		// 以参数传入当前的 h，后面对 h 的重新赋值（h2c）不影响 HTTPS 监听
+		go func(h http.Handler) {
+			log.Fatalf("TLS server failed: %v", serveTLS(":"+port, h))
+		}(h)
+	}
+
+	if port := os.Getenv("HEALTH_PORT"); port != "" {
+		serveHealthPort(":" + port)
+	}
+
+	if port := os.Getenv("TCP_PORT"); port != "" {
+		go func() {
+			log.Fatalf("TCP echo failed: %v", serveTCP(":"+port))
+		}()
+	}
+	if port := os.Getenv("UDP_PORT"); port != "" {
+		go func() {
+			log.Fatalf("UDP echo failed: %v", serveUDP(":"+port))
+		}()
+	}
+
+	if os.Getenv("H2C") == "true" {
+		log.Printf("h2c enabled on %s", addr)
+		h = h2c.NewHandler(h, &http2.Server{})
+	}
+	return http.ListenAndServe(addr, h)
+}
+
+// serveTLS 启动 HTTPS 监听，按 TLS_ALPN 声明 h2 / http/1.1
+func serveTLS(addr string, h http.Handler) error {
+	alpn := []string{"h2", "http/1.1"}
+	if v := os.Getenv("TLS_ALPN"); v != "" {
+		alpn = nil
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				alpn = append(alpn, p)
+			}
+		}
+	}
+
+	srv := &http.Server{
+		Addr:      addr,
+		Handler:   h,
+		TLSConfig: tlsConfig().Clone(),
+	}
+	srv.TLSConfig.NextProtos = alpn
+	h2 := false
+	for _, p := range alpn {
+		h2 = h2 || p == "h2"
+	}
+	if !h2 {
+		// 非 nil 的空 map 会关闭 net/http 自动启用的 HTTP/2
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+
+	log.Printf("TLS server listening on %s, ALPN %v", addr, alpn)
+	return srv.ListenAndServeTLS("", "")
+}
+
+// serveH3 启动 HTTP/3 监听，与 HTTPS 共用证书
+func serveH3(addr string, h http.Handler) error {
+	srv := &http3.Server{Addr: addr, Handler: h, TLSConfig: http3.ConfigureTLSConfig(tlsConfig())}
+	log.Printf("HTTP/3 server listening on %s (udp)", addr)
+	return srv.ListenAndServe()
+}
+
+// altSvc 告知客户端可改用 HTTP/3
+func altSvc(next http.Handler, port string) http.Handler {
+	value := fmt.Sprintf(`h3=":%s"; ma=86400`, port)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Alt-Svc", value)
+		next.ServeHTTP(w, r)
+	})
+}
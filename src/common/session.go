@@ -0,0 +1,117 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ---------- 会话保持（验证 Service sessionAffinity 与 APISIX cookie / chash） ----------
+
+const (
+	// sessionTTL 超过该时长未访问的会话不再统计
+	sessionTTL = 3 * time.Hour
+	// sessionMax 单实例最多记录的会话数
+	sessionMax = 10000
+)
+
+var sessionCount = NewGauge("sessions", "Sessions tracked by this instance.")
+
+// sessionStat 本实例对单个会话的统计
+type sessionStat struct {
+	firstSeen time.Time
+	lastSeen  time.Time
+	hits      int64
+}
+
+var sessions = struct {
+	sync.Mutex
+	m map[string]*sessionStat
+}{m: make(map[string]*sessionStat)}
+
+// sessionCookie 会话 cookie 名，由 SESSION_COOKIE 指定，默认 tiny_session
+func sessionCookie() string {
+	if v := os.Getenv("SESSION_COOKIE"); v != "" {
+		return v
+	}
+	return "tiny_session"
+}
+
+// touchSession 记录一次命中，返回此前的统计（首次命中返回 nil）与更新后的统计
+func touchSession(id string, now time.Time) (prev *sessionStat, cur sessionStat) {
+	sessions.Lock()
+	defer sessions.Unlock()
+	st, ok := sessions.m[id]
+	if ok && now.Sub(st.lastSeen) > sessionTTL {
+		ok = false
+	}
+	if ok {
+		p := *st
+		prev = &p
+	} else {
+		if len(sessions.m) >= sessionMax {
+			for k, v := range sessions.m {
+				if now.Sub(v.lastSeen) > sessionTTL {
+					delete(sessions.m, k)
+				}
+			}
+		}
+		st = &sessionStat{firstSeen: now}
+		if len(sessions.m) < sessionMax {
+			sessions.m[id] = st
+		}
+	}
+	st.lastSeen = now
+	st.hits++
+	sessionCount.Set(int64(len(sessions.m)))
+	return prev, *st
+}
+
+// Session 读取或下发会话 cookie，报告本实例是否见过该会话及命中次数
+//
+//	/session            无 cookie 时生成新会话并 Set-Cookie
+//	/session?reset=1    强制换一个新会话
+//	/session?max_age=60 cookie 有效期（秒），默认为浏览器会话 cookie
+func Session(w http.ResponseWriter, r *http.Request) {
+	name := sessionCookie()
+	id := ""
+	if c, err := r.Cookie(name); err == nil && r.URL.Query().Get("reset") == "" {
+		id = c.Value
+	}
+	issued := id == ""
+	if issued {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    id,
+			Path:     "/",
+			MaxAge:   QueryInt(r, "max_age", 0),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	now := time.Now()
+	prev, cur := touchSession(id, now)
+	data := map[string]interface{}{
+		"session":     id,
+		"cookie":      name,
+		"issued":      issued,
+		"seen_before": prev != nil,
+		"hits":        cur.hits,
+		"first_seen":  cur.firstSeen.Format(time.RFC3339Nano),
+		"pod":         PodName(),
+		"version":     Version(),
+		"client":      clientIP(r),
+	}
+	if prev != nil {
+		data["last_seen"] = prev.lastSeen.Format(time.RFC3339Nano)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: data})
+}
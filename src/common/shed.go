@@ -0,0 +1,93 @@
+package common
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ---------- 并发限制与过载保护（模拟过载后端，验证网关异常检测与 HPA） ----------
+
+var (
+	inflightRequests = NewGauge("inflight_requests", "Current requests being processed under MAX_INFLIGHT.")
+	queuedRequests   = NewGauge("queued_requests", "Current requests waiting for a MAX_INFLIGHT slot.")
+	shedRequests     = NewCounter("shed_requests_total", "Total requests rejected with 503 by the concurrency limit.")
+)
+
+// concurrencyLimit 限制同时处理的请求数，超出时排队，队列满或等待超时返回 503
+//
+//	MAX_INFLIGHT=50       同时处理的请求数，默认 0 不限制
+//	MAX_QUEUE=100         排队上限，默认 0 不排队
+//	QUEUE_TIMEOUT=2s      排队超时，默认 1s
+func concurrencyLimit(next http.Handler) http.Handler {
+	limit := EnvInt("MAX_INFLIGHT", 0)
+	if limit <= 0 {
+		return next
+	}
+	maxQueue := int64(EnvInt("MAX_QUEUE", 0))
+	timeout := time.Second
+	if v := os.Getenv("QUEUE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid QUEUE_TIMEOUT: %v", err)
+		}
+		timeout = d
+	}
+	log.Printf("concurrency limit %d, queue %d, queue timeout %s", limit, maxQueue, timeout)
+
+	slots := make(chan struct{}, limit)
+	var queued atomic.Int64
+	shed := func(w http.ResponseWriter, reason string) {
+		shedRequests.Inc()
+		w.Header().Set("Retry-After", "1")
+		WriteJSON(w, http.StatusServiceUnavailable, Resp{Code: 1, Msg: "overloaded: " + reason, Data: map[string]interface{}{
+			"inflight": len(slots),
+			"queued":   queued.Load(),
+			"pod":      PodName(),
+		}})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			if queued.Add(1) > maxQueue {
+				queued.Add(-1)
+				shed(w, "queue full")
+				return
+			}
+			queuedRequests.Inc()
+			t := time.NewTimer(timeout)
+			select {
+			case slots <- struct{}{}:
+				t.Stop()
+			case <-t.C:
+				queued.Add(-1)
+				queuedRequests.Dec()
+				shed(w, "queue timeout")
+				return
+			case <-r.Context().Done():
+				t.Stop()
+				queued.Add(-1)
+				queuedRequests.Dec()
+				return
+			}
+			queued.Add(-1)
+			queuedRequests.Dec()
+		}
+
+		inflightRequests.Inc()
+		defer func() {
+			inflightRequests.Dec()
+			<-slots
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ---------- 慢响应（分别验证网关的 connect / send / read 超时） ----------
+
+var slowRequests = NewGauge("slow_requests", "Current requests in /slow.")
+
+// Slow 按模式制造慢响应，body 为 size 字节的 pattern（默认 "."）
+//
+//	/slow/ttfb?delay=5s&size=1k       等待 delay 后才发送响应头和 body（首字节延迟）
+//	/slow/trickle?rate=100&size=1k    立即发送响应头，body 按每秒 rate 字节逐步发送
+//	/slow/stall?delay=30s&after=100   发送响应头和前 after 字节后停顿 delay（0 表示一直停顿），再发送剩余部分
+//	/slow/hang                        不返回任何内容，直到客户端断开
+func Slow(w http.ResponseWriter, r *http.Request) {
+	mode := PathParam(r, "/slow/")
+	size, err := parseSize(r.URL.Query().Get("size"))
+	if r.URL.Query().Get("size") == "" {
+		size, err = 1024, nil
+	}
+	if err != nil || size > bytesMaxSize {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("invalid size, max %d", bytesMaxSize)})
+		return
+	}
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		pattern = "."
+	}
+	body := &payload{size: size, pattern: []byte(pattern)}
+	delay := queryDuration(r, "delay", 5*time.Second)
+	ctx := r.Context()
+
+	slowRequests.Inc()
+	defer slowRequests.Dec()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/plain; charset=utf-8")
+	h.Set("Content-Length", strconv.FormatInt(size, 10))
+	rc := http.NewResponseController(w)
+
+	switch mode {
+	case "ttfb":
+		if !waitCtx(ctx, delay) {
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.Copy(w, body)
+
+	case "trickle":
+		rate := int64(QueryInt(r, "rate", 100))
+		if rate <= 0 {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "rate must be positive"})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = rc.Flush()
+		// 每 100ms 发送 rate/10 字节，速率很低时每次 1 字节
+		chunk := max(rate/10, 1)
+		interval := time.Duration(chunk) * time.Second / time.Duration(rate)
+		for sent := int64(0); sent < size; {
+			n, _ := io.CopyN(w, body, min(chunk, size-sent))
+			sent += n
+			if rc.Flush() != nil || (sent < size && !waitCtx(ctx, interval)) {
+				return
+			}
+		}
+
+	case "stall":
+		after := int64(QueryInt(r, "after", 0))
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.CopyN(w, body, min(after, size))
+		if rc.Flush() != nil || !waitCtx(ctx, delay) {
+			return
+		}
+		_, _ = io.Copy(w, body)
+
+	case "hang":
+		h.Del("Content-Type")
+		h.Del("Content-Length")
+		<-ctx.Done()
+
+	default:
+		h.Del("Content-Length")
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: fmt.Sprintf("unknown mode: %q, available: ttfb trickle stall hang", mode)})
+	}
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ---------- Server-Sent Events（验证网关不缓冲流式响应、空闲超时） ----------
+
+var sseStreams = NewGauge("sse_streams", "Current SSE streams.")
+
+// SSE 推送带 id 的事件流，断线重连时从 Last-Event-ID 之后继续
+//
+//	/sse?count=10&interval=1&heartbeat=15
+//	count     事件总数，0 表示不结束，默认 10
+//	interval  事件间隔，默认 1s
+//	heartbeat 心跳（注释行）间隔，默认 15s，0 表示关闭
+func SSE(w http.ResponseWriter, r *http.Request) {
+	count := QueryInt(r, "count", 10)
+	interval := queryDuration(r, "interval", time.Second)
+	heartbeat := queryDuration(r, "heartbeat", 15*time.Second)
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	// 断线重连时浏览器会带上最后收到的事件 id
+	lastID := 0
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastID, _ = strconv.Atoi(v)
+	}
+	if v := r.URL.Query().Get("last_event_id"); v != "" {
+		lastID, _ = strconv.Atoi(v)
+	}
+
+	rc := http.NewResponseController(w)
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	sseStreams.Inc()
+	defer sseStreams.Dec()
+
+	fmt.Fprintf(w, "retry: %d\n\n", interval.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events := time.NewTicker(interval)
+	defer events.Stop()
+	var beats <-chan time.Time
+	if heartbeat > 0 {
+		t := time.NewTicker(heartbeat)
+		defer t.Stop()
+		beats = t.C
+	}
+
+	for id := lastID + 1; count <= 0 || id <= count; {
+		select {
+		case <-r.Context().Done():
+			return
+		case t := <-beats:
+			fmt.Fprintf(w, ": heartbeat %s\n\n", t.Format(time.RFC3339))
+		case t := <-events.C:
+			data, _ := json.Marshal(map[string]interface{}{
+				"id":      id,
+				"pod":     PodName(),
+				"version": Version(),
+				"time":    t.Format(time.RFC3339Nano),
+			})
+			fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", id, data)
+			id++
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+	fmt.Fprint(w, "event: end\ndata: {}\n\n")
+	_ = rc.Flush()
+}
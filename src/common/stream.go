@@ -0,0 +1,263 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ---------- TCP / UDP 回显（验证 APISIX stream_routes 与 K8s UDP Service） ----------
+
+// streamMode 由 STREAM_MODE 指定
+//
+//	echo         原样回显（默认）
+//	banner       发送实例信息后关闭连接；UDP 对每个包回复实例信息
+//	banner-echo  先发送实例信息再回显；UDP 回复实例信息加原始内容
+var streamMode = "echo"
+
+// envStreamMode 读取 STREAM_MODE，非法时拒绝启动；由 ListenAndServe 调用
+func envStreamMode() string {
+	switch m := os.Getenv("STREAM_MODE"); m {
+	case "", "echo":
+		return "echo"
+	case "banner", "banner-echo":
+		return m
+	default:
+		log.Fatalf("Invalid STREAM_MODE: %s, available: echo banner banner-echo", m)
+		return ""
+	}
+}
+
+const (
+	// streamHistory 保留的已关闭 TCP 连接数
+	streamHistory = 100
+	// udpPeersMax 最多统计的 UDP 对端数
+	udpPeersMax = 1000
+)
+
+var (
+	tcpConnections      = NewGauge("tcp_connections", "Current raw TCP connections.")
+	tcpConnectionsTotal = NewCounter("tcp_connections_total", "Total accepted raw TCP connections.")
+	tcpBytesIn          = NewCounter("tcp_received_bytes_total", "Total bytes received on raw TCP listener.")
+	tcpBytesOut         = NewCounter("tcp_sent_bytes_total", "Total bytes sent on raw TCP listener.")
+	udpPacketsTotal     = NewCounter("udp_packets_total", "Total datagrams received on UDP listener.")
+	udpBytesIn          = NewCounter("udp_received_bytes_total", "Total bytes received on UDP listener.")
+)
+
+// tcpConnStat 单个 TCP 连接的统计
+type tcpConnStat struct {
+	id      int64
+	remote  string
+	local   string
+	start   time.Time
+	end     time.Time
+	in, out atomic.Int64
+}
+
+func (st *tcpConnStat) view() map[string]interface{} {
+	v := map[string]interface{}{
+		"id":        st.id,
+		"remote":    st.remote,
+		"local":     st.local,
+		"start":     st.start.Format(time.RFC3339Nano),
+		"bytes_in":  st.in.Load(),
+		"bytes_out": st.out.Load(),
+	}
+	if !st.end.IsZero() {
+		v["end"] = st.end.Format(time.RFC3339Nano)
+		v["duration"] = st.end.Sub(st.start).String()
+	}
+	return v
+}
+
+// udpPeerStat 单个 UDP 对端的统计
+type udpPeerStat struct {
+	Remote     string    `json:"remote"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	PacketsIn  int64     `json:"packets_in"`
+	PacketsOut int64     `json:"packets_out"`
+	BytesIn    int64     `json:"bytes_in"`
+	BytesOut   int64     `json:"bytes_out"`
+}
+
+var streamStats = struct {
+	sync.Mutex
+	seq    int64
+	active map[int64]*tcpConnStat
+	closed []*tcpConnStat
+	udp    map[string]*udpPeerStat
+}{active: make(map[int64]*tcpConnStat), udp: make(map[string]*udpPeerStat)}
+
+// banner 连接建立时发送的实例信息
+func banner(proto, remote string) []byte {
+	return []byte(fmt.Sprintf("demo-go-tiny %s pod=%s node=%s version=%s client=%s\n",
+		proto, PodName(), os.Getenv("NODE_NAME"), Version(), remote))
+}
+
+// serveTCP 启动 TCP 回显监听
+func serveTCP(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("TCP echo listening on %s, mode %q", addr, streamMode)
+	var backoff time.Duration
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			// 与 net/http 一致，EMFILE 等临时错误退避后重试，不让整个进程退出
+			backoff = min(max(backoff*2, 5*time.Millisecond), time.Second)
+			log.Printf("TCP echo accept error: %v; retrying in %s", err, backoff)
+			time.Sleep(backoff)
+			continue
+		}
+		backoff = 0
+		go handleTCP(conn)
+	}
+}
+
+func handleTCP(conn net.Conn) {
+	defer conn.Close()
+
+	streamStats.Lock()
+	streamStats.seq++
+	st := &tcpConnStat{id: streamStats.seq, remote: conn.RemoteAddr().String(), local: conn.LocalAddr().String(), start: time.Now()}
+	streamStats.active[st.id] = st
+	streamStats.Unlock()
+	tcpConnections.Inc()
+	tcpConnectionsTotal.Inc()
+
+	defer func() {
+		tcpConnections.Dec()
+		streamStats.Lock()
+		delete(streamStats.active, st.id)
+		st.end = time.Now()
+		streamStats.closed = append(streamStats.closed, st)
+		if len(streamStats.closed) > streamHistory {
+			streamStats.closed = streamStats.closed[1:]
+		}
+		streamStats.Unlock()
+	}()
+
+	if streamMode == "banner" || streamMode == "banner-echo" {
+		n, err := conn.Write(banner("tcp", st.remote))
+		st.out.Add(int64(n))
+		tcpBytesOut.Add(int64(n))
+		if err != nil || streamMode == "banner" {
+			return
+		}
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			st.in.Add(int64(n))
+			tcpBytesIn.Add(int64(n))
+			m, werr := conn.Write(buf[:n])
+			st.out.Add(int64(m))
+			tcpBytesOut.Add(int64(m))
+			if werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("tcp %s: %v", st.remote, err)
+			}
+			return
+		}
+	}
+}
+
+// serveUDP 启动 UDP 回显监听
+func serveUDP(addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("UDP echo listening on %s, mode %q", addr, streamMode)
+	buf := make([]byte, 64*1024)
+	for {
+		n, remote, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		udpPacketsTotal.Inc()
+		udpBytesIn.Add(int64(n))
+
+		var reply []byte
+		switch streamMode {
+		case "banner":
+			reply = banner("udp", remote.String())
+		case "banner-echo":
+			reply = append(banner("udp", remote.String()), buf[:n]...)
+		default:
+			reply = buf[:n]
+		}
+		m, _ := conn.WriteTo(reply, remote)
+
+		now := time.Now()
+		streamStats.Lock()
+		st, ok := streamStats.udp[remote.String()]
+		if !ok && len(streamStats.udp) < udpPeersMax {
+			st = &udpPeerStat{Remote: remote.String(), FirstSeen: now}
+			streamStats.udp[remote.String()] = st
+		}
+		if st != nil {
+			st.LastSeen = now
+			st.PacketsIn++
+			st.BytesIn += int64(n)
+			if m > 0 {
+				st.PacketsOut++
+				st.BytesOut += int64(m)
+			}
+		}
+		streamStats.Unlock()
+	}
+}
+
+// StreamStats 返回 TCP 活跃与最近关闭的连接、UDP 各对端的统计
+func StreamStats(w http.ResponseWriter, _ *http.Request) {
+	streamStats.Lock()
+	ids := make([]int64, 0, len(streamStats.active))
+	for id := range streamStats.active {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	active := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		active = append(active, streamStats.active[id].view())
+	}
+	closed := make([]map[string]interface{}, 0, len(streamStats.closed))
+	for _, st := range streamStats.closed {
+		closed = append(closed, st.view())
+	}
+	udp := make([]udpPeerStat, 0, len(streamStats.udp))
+	for _, st := range streamStats.udp {
+		udp = append(udp, *st)
+	}
+	streamStats.Unlock()
+	sort.Slice(udp, func(i, j int) bool { return udp[i].FirstSeen.Before(udp[j].FirstSeen) })
+
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: map[string]interface{}{
+		"mode":       streamMode,
+		"tcp_port":   os.Getenv("TCP_PORT"),
+		"udp_port":   os.Getenv("UDP_PORT"),
+		"tcp_active": active,
+		"tcp_closed": closed,
+		"udp_peers":  udp,
+	}})
+}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ---------- 带宽限制（模拟慢速链路，验证下载超时与 limit-rate 类功能） ----------
+
+// envThrottleBps 全局响应带宽（字节/秒），由 THROTTLE_BPS 指定，支持 k/m 后缀，默认不限速，非法时拒绝启动
+func envThrottleBps() int64 {
+	v := os.Getenv("THROTTLE_BPS")
+	if v == "" {
+		return 0
+	}
+	n, err := parseSize(v)
+	if err != nil {
+		log.Fatalf("Invalid THROTTLE_BPS: %v", err)
+	}
+	return n
+}
+
+// pace 已传输 n 字节后等待到按 rate 字节/秒应到的时刻；客户端断开时返回 false
+func pace(ctx context.Context, start time.Time, n, rate int64) bool {
+	// 按浮点秒计算，避免 n 较大（约 9.2 GB 以上）时 Duration 相乘溢出
+	due := start.Add(time.Duration(float64(n) / float64(rate) * float64(time.Second)))
+	if d := time.Until(due); d > 0 {
+		return waitCtx(ctx, d)
+	}
+	return true
+}
+
+// throttleWriter 按 rate 字节/秒写出响应，每 100ms 的量刷新一次
+type throttleWriter struct {
+	http.ResponseWriter
+	ctx   context.Context
+	rate  int64
+	start time.Time
+	n     int64
+}
+
+func (w *throttleWriter) Write(p []byte) (int, error) {
+	chunk := max(w.rate/10, 1)
+	written := 0
+	for len(p) > 0 {
+		b := p[:min(int64(len(p)), chunk)]
+		// 先等待再发送，N 字节的总耗时为 N/rate
+		if !pace(w.ctx, w.start, w.n+int64(len(b)), w.rate) {
+			return written, w.ctx.Err()
+		}
+		n, err := w.ResponseWriter.Write(b)
+		written += n
+		w.n += int64(n)
+		if err != nil {
+			return written, err
+		}
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (w *throttleWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *throttleWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *throttleWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// throttle 按 THROTTLE_BPS 或请求头 X-Throttle-Bps（优先，0 表示不限速）限制响应带宽
+func throttle(next http.Handler) http.Handler {
+	throttleBps := envThrottleBps()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rate := throttleBps
+		if v := r.Header.Get("X-Throttle-Bps"); v != "" {
+			n, err := parseSize(v)
+			if err != nil {
+				WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "invalid X-Throttle-Bps: " + err.Error()})
+				return
+			}
+			rate = n
+		}
+		if rate <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("X-Throttle-Bps", strconv.FormatInt(rate, 10))
+		next.ServeHTTP(&throttleWriter{ResponseWriter: w, ctx: r.Context(), rate: rate, start: time.Now()}, r)
+	})
+}
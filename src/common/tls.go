@@ -0,0 +1,301 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------- TLS 证书与双向认证（验证 APISIX upstream mTLS 与 ssl 对象） ----------
+
+// clientAuthModes TLS_CLIENT_AUTH 可选值
+//
+//	none     不要求客户端证书（默认）
+//	request  请求但不强制，由处理函数校验并报告结果
+//	require  必须提供，由处理函数校验并报告结果
+//	verify   必须提供且握手阶段校验，失败直接断开
+var clientAuthModes = map[string]tls.ClientAuthType{
+	"none":    tls.NoClientCert,
+	"request": tls.RequestClientCert,
+	"require": tls.RequireAnyClientCert,
+	"verify":  tls.RequireAndVerifyClientCert,
+}
+
+var (
+	tlsOnce sync.Once
+	tlsConf *tls.Config
+	// tlsCA 自动生成的 CA，加载外部证书时为 nil
+	tlsCA *x509.Certificate
+	// tlsCAKey 自动生成的 CA 私钥，用于签发客户端测试证书
+	tlsCAKey *ecdsa.PrivateKey
+	// clientCAs 校验客户端证书用的根证书
+	clientCAs *x509.CertPool
+)
+
+// tlsConfig HTTPS 与 HTTP/3 共用的配置
+//
+//	TLS_CERT / TLS_KEY   服务端证书（如挂载的 Secret），未设置时启动时自动生成自签 CA 与服务端证书
+//	TLS_SANS             自动生成证书时额外的 SAN，逗号分隔，如 go-tiny.go-tiny.svc,10.0.0.1
+//	TLS_CLIENT_AUTH      客户端证书模式 none / request / require / verify
+//	TLS_CLIENT_CA        校验客户端证书的 CA 文件，未设置时使用自动生成的 CA
+func tlsConfig() *tls.Config {
+	tlsOnce.Do(func() {
+		var cert tls.Certificate
+		var err error
+		if certFile, keyFile := os.Getenv("TLS_CERT"), os.Getenv("TLS_KEY"); certFile != "" && keyFile != "" {
+			if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+				log.Fatalf("Failed to load TLS certificate: %v", err)
+			}
+			log.Printf("TLS certificate loaded from %s", certFile)
+		} else {
+			if cert, err = selfSignedCert(); err != nil {
+				log.Fatalf("Failed to generate TLS certificate: %v", err)
+			}
+			log.Printf("TLS certificate generated, issuer %q", tlsCA.Subject.CommonName)
+		}
+
+		clientCAs = x509.NewCertPool()
+		if f := os.Getenv("TLS_CLIENT_CA"); f != "" {
+			data, err := os.ReadFile(f)
+			if err != nil || !clientCAs.AppendCertsFromPEM(data) {
+				log.Fatalf("Failed to load TLS_CLIENT_CA %s: %v", f, err)
+			}
+		} else if tlsCA != nil {
+			clientCAs.AddCert(tlsCA)
+		}
+
+		mode := os.Getenv("TLS_CLIENT_AUTH")
+		if mode == "" {
+			mode = "none"
+		}
+		auth, ok := clientAuthModes[mode]
+		if !ok {
+			log.Fatalf("Invalid TLS_CLIENT_AUTH: %s, available: none request require verify", mode)
+		}
+		// 使用 TLS_CERT 时没有自动生成的 CA，verify 模式必须显式指定 TLS_CLIENT_CA，否则所有握手都会失败
+		if mode == "verify" && os.Getenv("TLS_CLIENT_CA") == "" && tlsCA == nil {
+			log.Fatalf("TLS_CLIENT_AUTH=verify requires TLS_CLIENT_CA when TLS_CERT is set")
+		}
+
+		tlsConf = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   auth,
+			ClientCAs:    clientCAs,
+		}
+	})
+	return tlsConf
+}
+
+// selfSignedCert 生成自签 CA，并用它签发服务端证书
+func selfSignedCert() (tls.Certificate, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          randSerial(),
+		Subject:               pkix.Name{CommonName: "demo-go-tiny CA", Organization: []string{"demo-go-tiny"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if tlsCA, err = x509.ParseCertificate(caDER); err != nil {
+		return tls.Certificate{}, err
+	}
+	tlsCAKey = caKey
+
+	hostname, _ := os.Hostname()
+	dns := []string{"localhost"}
+	ips := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	for _, san := range append([]string{hostname, PodName()}, strings.Split(os.Getenv("TLS_SANS"), ",")...) {
+		if san = strings.TrimSpace(san); san == "" {
+			continue
+		}
+		if ip := net.ParseIP(san); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dns = append(dns, san)
+		}
+	}
+
+	cn := PodName()
+	if cn == "" {
+		cn = "localhost"
+	}
+	return issueCert(pkix.Name{CommonName: cn, Organization: []string{"demo-go-tiny"}}, dns, ips, x509.ExtKeyUsageServerAuth)
+}
+
+// issueCert 用自动生成的 CA 签发证书
+func issueCert(subject pkix.Name, dns []string, ips []net.IP, usage x509.ExtKeyUsage) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: randSerial(),
+		Subject:      subject,
+		DNSNames:     dns,
+		IPAddresses:  ips,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tlsCA, &key.PublicKey, tlsCAKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der, tlsCA.Raw}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+func randSerial() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+// TLSInfo 返回本次请求的 TLS 协商结果与客户端证书信息，非 TLS 请求返回 nil
+func TLSInfo(r *http.Request) map[string]interface{} {
+	if r.TLS == nil {
+		return nil
+	}
+	info := map[string]interface{}{
+		"version":      tls.VersionName(r.TLS.Version),
+		"cipher_suite": tls.CipherSuiteName(r.TLS.CipherSuite),
+		"alpn":         r.TLS.NegotiatedProtocol,
+		"server_name":  r.TLS.ServerName,
+		"resumed":      r.TLS.DidResume,
+	}
+	if len(r.TLS.PeerCertificates) > 0 {
+		info["client_cert"] = clientCertInfo(r.TLS)
+	}
+	return info
+}
+
+// clientCertInfo 客户端证书的主题、SAN、指纹与校验结果
+func clientCertInfo(cs *tls.ConnectionState) map[string]interface{} {
+	leaf := cs.PeerCertificates[0]
+	fp := sha256.Sum256(leaf.Raw)
+	info := map[string]interface{}{
+		"subject":            leaf.Subject.String(),
+		"issuer":             leaf.Issuer.String(),
+		"serial":             leaf.SerialNumber.Text(16),
+		"not_before":         leaf.NotBefore.Format(time.RFC3339),
+		"not_after":          leaf.NotAfter.Format(time.RFC3339),
+		"fingerprint_sha256": hex.EncodeToString(fp[:]),
+		"sans": map[string]interface{}{
+			"dns":   leaf.DNSNames,
+			"ip":    leaf.IPAddresses,
+			"email": leaf.EmailAddresses,
+			"uri":   leaf.URIs,
+		},
+	}
+
+	// verify 模式握手时已校验；request / require 模式在这里补充校验
+	if len(cs.VerifiedChains) > 0 {
+		info["verified"] = true
+		return info
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	info["verified"] = err == nil
+	if err != nil {
+		info["verify_error"] = err.Error()
+	}
+	return info
+}
+
+// TLS 返回本次连接的 TLS 信息，包括客户端证书
+func TLS(w http.ResponseWriter, r *http.Request) {
+	if r.TLS == nil {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "not a TLS request, set TLS_PORT and use https"})
+		return
+	}
+	mode := os.Getenv("TLS_CLIENT_AUTH")
+	if mode == "" {
+		mode = "none"
+	}
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: map[string]interface{}{
+		"client_auth": mode,
+		"tls":         TLSInfo(r),
+	}})
+}
+
+// TLSCA 下载自动生成的 CA 证书，供网关或客户端信任
+func TLSCA(w http.ResponseWriter, _ *http.Request) {
+	if tlsCA == nil {
+		WriteJSON(w, http.StatusNotFound, Resp{Code: 1, Msg: "no generated CA, TLS is disabled or TLS_CERT is set"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: tlsCA.Raw})
+}
+
+// tlsIssueClientCert 是否开放 /tls/client-cert，由 TLS_ISSUE_CLIENT_CERT=true 开启
+//
+// 签发的证书能通过 TLS_CLIENT_AUTH=verify 的默认校验，因此默认关闭，开启后同样受 ADMIN_TOKEN 保护
+var tlsIssueClientCert = os.Getenv("TLS_ISSUE_CLIENT_CERT") == "true"
+
+// TLSClientCert 用自动生成的 CA 签发客户端测试证书，返回证书与私钥 PEM
+//
+//	/tls/client-cert?cn=apisix
+func TLSClientCert(w http.ResponseWriter, r *http.Request) {
+	if !tlsIssueClientCert {
+		WriteJSON(w, http.StatusForbidden, Resp{Code: 1, Msg: "issuing client certificates is disabled, set TLS_ISSUE_CLIENT_CERT=true"})
+		return
+	}
+	if !adminAllowed(w, r) {
+		return
+	}
+	if tlsCA == nil {
+		WriteJSON(w, http.StatusNotFound, Resp{Code: 1, Msg: "no generated CA, TLS is disabled or TLS_CERT is set"})
+		return
+	}
+	cn := r.URL.Query().Get("cn")
+	if cn == "" {
+		cn = "client"
+	}
+	cert, err := issueCert(pkix.Name{CommonName: cn, Organization: []string{"demo-go-tiny"}}, []string{cn}, nil, x509.ExtKeyUsageClientAuth)
+	if err != nil {
+		WriteJSON(w, http.StatusInternalServerError, Resp{Code: 1, Msg: err.Error()})
+		return
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		WriteJSON(w, http.StatusInternalServerError, Resp{Code: 1, Msg: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	_ = pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	_ = pem.Encode(w, &pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ---------- 上传（验证网关请求缓冲、client_body_timeout 与大小限制） ----------
+
+// uploadMaxSize 上传的最大字节数，由 UPLOAD_MAX_SIZE 指定，默认 0 不限制
+var uploadMaxSize = int64(EnvInt("UPLOAD_MAX_SIZE", 0))
+
+// slowReader 按 rate 字节/秒读取，读到 stallAfter 字节时停顿 stall
+type slowReader struct {
+	ctx        context.Context
+	r          io.Reader
+	rate       int64
+	stallAfter int64
+	stall      time.Duration
+	stalled    bool
+	start      time.Time
+	n          int64
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	if s.stallAfter > 0 && !s.stalled && s.n >= s.stallAfter {
+		s.stalled = true
+		if !waitCtx(s.ctx, s.stall) {
+			return 0, s.ctx.Err()
+		}
+		// 停顿不计入限速
+		s.start = s.start.Add(s.stall)
+	}
+	if s.stallAfter > 0 && !s.stalled && int64(len(p)) > s.stallAfter-s.n {
+		p = p[:s.stallAfter-s.n]
+	}
+	if s.rate > 0 {
+		// 每次最多读 100ms 的量
+		p = p[:min(int64(len(p)), max(s.rate/10, 1))]
+	}
+	n, err := s.r.Read(p)
+	s.n += int64(n)
+	if s.rate > 0 && !pace(s.ctx, s.start, s.n, s.rate) {
+		return n, s.ctx.Err()
+	}
+	return n, err
+}
+
+// hashCounter 计算 SHA-256 并统计字节数
+type hashCounter struct {
+	hash.Hash
+	n int64
+}
+
+func (h *hashCounter) Write(p []byte) (int, error) {
+	h.n += int64(len(p))
+	return h.Hash.Write(p)
+}
+
+// Upload 读取请求体并返回字节数、SHA-256 与吞吐量，multipart 逐个 part 统计
+//
+//	/upload?rate=100k                  按每秒 rate 字节读取，默认不限速
+//	/upload?stall_after=1m&stall=30s   读到 stall_after 字节后停顿 stall（0 表示一直停顿到客户端断开）
+func Upload(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var stallAfter int64
+	if v := q.Get("stall_after"); v != "" {
+		var err error
+		if stallAfter, err = parseSize(v); err != nil {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: err.Error()})
+			return
+		}
+	}
+	var rate int64
+	if v := q.Get("rate"); v != "" {
+		var err error
+		if rate, err = parseSize(v); err != nil {
+			WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: err.Error()})
+			return
+		}
+	}
+	if uploadMaxSize > 0 && r.ContentLength > uploadMaxSize {
+		WriteJSON(w, http.StatusRequestEntityTooLarge, Resp{Code: 1, Msg: fmt.Sprintf("content length %d exceeds UPLOAD_MAX_SIZE %d", r.ContentLength, uploadMaxSize)})
+		return
+	}
+
+	start := time.Now()
+	var body io.Reader = r.Body
+	if uploadMaxSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, uploadMaxSize)
+	}
+	raw := &hashCounter{Hash: sha256.New()}
+	body = io.TeeReader(&slowReader{
+		ctx:        r.Context(),
+		r:          body,
+		rate:       rate,
+		stallAfter: stallAfter,
+		stall:      queryDuration(r, "stall", 30*time.Second),
+		start:      start,
+	}, raw)
+
+	data := map[string]interface{}{
+		"content_type":   r.Header.Get("Content-Type"),
+		"content_length": r.ContentLength,
+	}
+	var err error
+	mt, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if strings.HasPrefix(mt, "multipart/") && params["boundary"] != "" {
+		data["parts"], err = uploadParts(body, params["boundary"])
+	} else {
+		_, err = io.Copy(io.Discard, body)
+	}
+
+	elapsed := time.Since(start)
+	data["bytes"] = raw.n
+	data["sha256"] = hex.EncodeToString(raw.Sum(nil))
+	data["duration"] = elapsed.String()
+	data["throughput_bps"] = int64(float64(raw.n) / max(elapsed.Seconds(), 1e-9))
+	if err != nil {
+		data["error"] = err.Error()
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		WriteJSON(w, status, Resp{Code: 1, Msg: "read body: " + err.Error(), Data: data})
+		return
+	}
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: data})
+}
+
+// uploadParts 流式读取 multipart，返回每个 part 的字段名、文件名、大小与 SHA-256
+func uploadParts(body io.Reader, boundary string) ([]map[string]interface{}, error) {
+	parts := []map[string]interface{}{}
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return parts, err
+		}
+		sum := &hashCounter{Hash: sha256.New()}
+		_, err = io.Copy(sum, part)
+		parts = append(parts, map[string]interface{}{
+			"field":        part.FormName(),
+			"filename":     part.FileName(),
+			"content_type": part.Header.Get("Content-Type"),
+			"size":         sum.n,
+			"sha256":       hex.EncodeToString(sum.Sum(nil)),
+		})
+		if err != nil {
+			return parts, err
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ---------- 排队模型（M/M/c，延迟随并发上升，给 HPA 与压测真实的饱和曲线） ----------
+
+// workWorkers 模拟的服务能力（worker 数），由 WORK_WORKERS 指定，默认 4
+var workWorkers = max(EnvInt("WORK_WORKERS", 4), 1)
+
+var (
+	workSlots    = make(chan struct{}, workWorkers)
+	workBusy     = NewGauge("work_busy_workers", "Simulated workers currently serving /work.")
+	workQueued   = NewGauge("work_queued_requests", "Requests waiting for a simulated worker.")
+	workRequests = NewCounter("work_requests_total", "Total requests served by /work.")
+)
+
+// serviceTime 按分布采样服务时间，均值为 mean
+//
+//	exp      指数分布（默认，M/M/c）
+//	fixed    固定值（M/D/c）
+//	uniform  [0, 2*mean) 均匀分布
+//	normal   正态分布，标准差 mean/4，截断为非负
+func serviceTime(dist string, mean time.Duration) (time.Duration, error) {
+	m := float64(mean)
+	switch dist {
+	case "", "exp":
+		return time.Duration(rand.ExpFloat64() * m), nil
+	case "fixed":
+		return mean, nil
+	case "uniform":
+		return time.Duration(rand.Float64() * 2 * m), nil
+	case "normal":
+		return time.Duration(max(rand.NormFloat64()*m/4+m, 0)), nil
+	}
+	return 0, fmt.Errorf("unknown dist: %q, available: exp fixed uniform normal", dist)
+}
+
+// burn 占用 CPU 直到 d 过去
+func burn(d time.Duration) {
+	x := uint64(1)
+	for end := time.Now().Add(d); time.Now().Before(end); {
+		for i := 0; i < 1000; i++ {
+			x = splitmix64(x)
+		}
+	}
+	_ = x
+}
+
+// Work 排队等待空闲 worker，再按分布采样的服务时间处理
+//
+//	/work?mean=50ms&dist=exp&cpu=false
+//	mean  平均服务时间，默认 50ms
+//	dist  服务时间分布 exp / fixed / uniform / normal
+//	cpu   true 时服务期间真实占用 CPU，否则只 sleep
+func Work(w http.ResponseWriter, r *http.Request) {
+	mean := queryDuration(r, "mean", 50*time.Millisecond)
+	service, err := serviceTime(r.URL.Query().Get("dist"), mean)
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: err.Error()})
+		return
+	}
+	cpu := r.URL.Query().Get("cpu") == "true"
+
+	arrival := time.Now()
+	queuedAhead := workQueued.Value()
+	workQueued.Inc()
+	select {
+	case workSlots <- struct{}{}:
+		workQueued.Dec()
+	case <-r.Context().Done():
+		workQueued.Dec()
+		return
+	}
+	wait := time.Since(arrival)
+	workBusy.Inc()
+
+	start := time.Now()
+	if cpu {
+		burn(service)
+	} else {
+		select {
+		case <-time.After(service):
+		case <-r.Context().Done():
+		}
+	}
+	workBusy.Dec()
+	<-workSlots
+	workRequests.Inc()
+
+	WriteJSON(w, http.StatusOK, Resp{Code: 0, Data: map[string]interface{}{
+		"workers":      workWorkers,
+		"queued_ahead": queuedAhead,
+		"wait_ms":      float64(wait.Microseconds()) / 1000,
+		"service_ms":   float64(time.Since(start).Microseconds()) / 1000,
+		"total_ms":     float64(time.Since(arrival).Microseconds()) / 1000,
+		"cpu":          cpu,
+		"pod":          PodName(),
+	}})
+}
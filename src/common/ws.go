@@ -0,0 +1,160 @@
+package common
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ---------- WebSocket（验证 APISIX enable_websocket） ----------
+
+var upgrader = websocket.Upgrader{
+	// 测试服务，允许任意来源
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+var (
+	wsConnections      = NewGauge("ws_connections", "Current WebSocket connections.")
+	wsConnectionsTotal = NewCounter("ws_connections_total", "Total accepted WebSocket connections.")
+	wsMessagesTotal    = NewCounter("ws_messages_total", "Total WebSocket messages received.")
+	wsConnSeq          atomic.Int64
+)
+
+// wsConn 单个连接，写操作需加锁（gorilla/websocket 不支持并发写）
+type wsConn struct {
+	id   int64
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *wsConn) write(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteMessage(messageType, data)
+}
+
+func (c *wsConn) writeJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+// wsHub broadcast 模式下的所有连接
+var wsHub = struct {
+	sync.Mutex
+	conns map[*wsConn]struct{}
+}{conns: make(map[*wsConn]struct{})}
+
+func broadcast(messageType int, data []byte) {
+	wsHub.Lock()
+	conns := make([]*wsConn, 0, len(wsHub.conns))
+	for c := range wsHub.conns {
+		conns = append(conns, c)
+	}
+	wsHub.Unlock()
+	for _, c := range conns {
+		_ = c.write(messageType, data)
+	}
+}
+
+// WebSocket 建立连接后先发送实例信息，再按 mode 工作
+//
+//	/ws?mode=echo                   原样回显（默认）
+//	/ws?mode=broadcast              消息广播给所有 broadcast 连接
+//	/ws?mode=push&interval=5        每 interval 秒主动推送一条消息
+func WebSocket(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = "echo"
+	}
+	if mode != "echo" && mode != "broadcast" && mode != "push" {
+		WriteJSON(w, http.StatusBadRequest, Resp{Code: 1, Msg: "invalid mode: " + mode + ", available: echo broadcast push"})
+		return
+	}
+	interval := QueryInt(r, "interval", 5)
+	if interval <= 0 {
+		interval = 5
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 失败时已写出错误响应
+		log.Printf("websocket upgrade failed: %v", err)
+		return
+	}
+	c := &wsConn{id: wsConnSeq.Add(1), conn: conn}
+	wsConnections.Inc()
+	wsConnectionsTotal.Inc()
+	defer func() {
+		wsConnections.Dec()
+		_ = conn.Close()
+	}()
+
+	_ = c.writeJSON(map[string]interface{}{
+		"type":     "welcome",
+		"conn_id":  c.id,
+		"mode":     mode,
+		"pod":      PodName(),
+		"version":  Version(),
+		"client":   clientIP(r),
+		"protocol": r.Proto,
+	})
+
+	switch mode {
+	case "broadcast":
+		wsHub.Lock()
+		wsHub.conns[c] = struct{}{}
+		wsHub.Unlock()
+		defer func() {
+			wsHub.Lock()
+			delete(wsHub.conns, c)
+			wsHub.Unlock()
+		}()
+	case "push":
+		done := make(chan struct{})
+		defer close(done)
+		go wsPush(c, time.Duration(interval)*time.Second, done)
+	}
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		wsMessagesTotal.Inc()
+		switch mode {
+		case "echo":
+			err = c.write(messageType, data)
+		case "broadcast":
+			broadcast(messageType, data)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wsPush 定时推送，直到连接关闭
+func wsPush(c *wsConn, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for seq := 1; ; seq++ {
+		select {
+		case <-done:
+			return
+		case t := <-ticker.C:
+			if err := c.writeJSON(map[string]interface{}{
+				"type": "push",
+				"seq":  seq,
+				"pod":  PodName(),
+				"time": t.Format(time.RFC3339Nano),
+			}); err != nil {
+				return
+			}
+		}
+	}
+}
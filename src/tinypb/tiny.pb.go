@@ -0,0 +1,589 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: tiny.proto
+
+// 与 HTTP 接口对应的 gRPC 服务，供 APISIX grpc 上游、grpc-transcode 与 K8s gRPC 探针验证
+
+package tinypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EchoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EchoRequest) Reset() {
+	*x = EchoRequest{}
+	mi := &file_tiny_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoRequest) ProtoMessage() {}
+
+func (x *EchoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoRequest.ProtoReflect.Descriptor instead.
+func (*EchoRequest) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EchoRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type EchoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Pod           string                 `protobuf:"bytes,3,opt,name=pod,proto3" json:"pod,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EchoResponse) Reset() {
+	*x = EchoResponse{}
+	mi := &file_tiny_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EchoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EchoResponse) ProtoMessage() {}
+
+func (x *EchoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EchoResponse.ProtoReflect.Descriptor instead.
+func (*EchoResponse) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EchoResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EchoResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *EchoResponse) GetPod() string {
+	if x != nil {
+		return x.Pod
+	}
+	return ""
+}
+
+func (x *EchoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type DelayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ms            int64                  `protobuf:"varint,1,opt,name=ms,proto3" json:"ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelayRequest) Reset() {
+	*x = DelayRequest{}
+	mi := &file_tiny_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelayRequest) ProtoMessage() {}
+
+func (x *DelayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelayRequest.ProtoReflect.Descriptor instead.
+func (*DelayRequest) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DelayRequest) GetMs() int64 {
+	if x != nil {
+		return x.Ms
+	}
+	return 0
+}
+
+type DelayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	SleptMs       int64                  `protobuf:"varint,2,opt,name=slept_ms,json=sleptMs,proto3" json:"slept_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelayResponse) Reset() {
+	*x = DelayResponse{}
+	mi := &file_tiny_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelayResponse) ProtoMessage() {}
+
+func (x *DelayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelayResponse.ProtoReflect.Descriptor instead.
+func (*DelayResponse) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DelayResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *DelayResponse) GetSleptMs() int64 {
+	if x != nil {
+		return x.SleptMs
+	}
+	return 0
+}
+
+type StatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// google.rpc.Code，0 表示 OK
+	Code          int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_tiny_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StatusRequest) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *StatusRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_tiny_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StatusResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+type ClientInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientInfoRequest) Reset() {
+	*x = ClientInfoRequest{}
+	mi := &file_tiny_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientInfoRequest) ProtoMessage() {}
+
+func (x *ClientInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientInfoRequest.ProtoReflect.Descriptor instead.
+func (*ClientInfoRequest) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{6}
+}
+
+type ClientInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Peer          string                 `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	ClientIp      string                 `protobuf:"bytes,2,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	Authority     string                 `protobuf:"bytes,3,opt,name=authority,proto3" json:"authority,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PodName       string                 `protobuf:"bytes,6,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
+	NodeName      string                 `protobuf:"bytes,7,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	Version       string                 `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+	StartTime     string                 `protobuf:"bytes,9,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientInfoResponse) Reset() {
+	*x = ClientInfoResponse{}
+	mi := &file_tiny_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientInfoResponse) ProtoMessage() {}
+
+func (x *ClientInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tiny_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientInfoResponse.ProtoReflect.Descriptor instead.
+func (*ClientInfoResponse) Descriptor() ([]byte, []int) {
+	return file_tiny_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ClientInfoResponse) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetAuthority() string {
+	if x != nil {
+		return x.Authority
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ClientInfoResponse) GetPodName() string {
+	if x != nil {
+		return x.PodName
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ClientInfoResponse) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+var File_tiny_proto protoreflect.FileDescriptor
+
+const file_tiny_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"tiny.proto\x12\atiny.v1\"'\n" +
+	"\vEchoRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xd2\x01\n" +
+	"\fEchoResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12?\n" +
+	"\bmetadata\x18\x02 \x03(\v2#.tiny.v1.EchoResponse.MetadataEntryR\bmetadata\x12\x10\n" +
+	"\x03pod\x18\x03 \x01(\tR\x03pod\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1e\n" +
+	"\fDelayRequest\x12\x0e\n" +
+	"\x02ms\x18\x01 \x01(\x03R\x02ms\"<\n" +
+	"\rDelayResponse\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\x12\x19\n" +
+	"\bslept_ms\x18\x02 \x01(\x03R\asleptMs\"=\n" +
+	"\rStatusRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\"\n" +
+	"\x0eStatusResponse\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\"\x13\n" +
+	"\x11ClientInfoRequest\"\xf7\x02\n" +
+	"\x12ClientInfoResponse\x12\x12\n" +
+	"\x04peer\x18\x01 \x01(\tR\x04peer\x12\x1b\n" +
+	"\tclient_ip\x18\x02 \x01(\tR\bclientIp\x12\x1c\n" +
+	"\tauthority\x18\x03 \x01(\tR\tauthority\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\x12E\n" +
+	"\bmetadata\x18\x05 \x03(\v2).tiny.v1.ClientInfoResponse.MetadataEntryR\bmetadata\x12\x19\n" +
+	"\bpod_name\x18\x06 \x01(\tR\apodName\x12\x1b\n" +
+	"\tnode_name\x18\a \x01(\tR\bnodeName\x12\x18\n" +
+	"\aversion\x18\b \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\t \x01(\tR\tstartTime\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xf5\x01\n" +
+	"\x04Tiny\x123\n" +
+	"\x04Echo\x12\x14.tiny.v1.EchoRequest\x1a\x15.tiny.v1.EchoResponse\x126\n" +
+	"\x05Delay\x12\x15.tiny.v1.DelayRequest\x1a\x16.tiny.v1.DelayResponse\x129\n" +
+	"\x06Status\x12\x16.tiny.v1.StatusRequest\x1a\x17.tiny.v1.StatusResponse\x12E\n" +
+	"\n" +
+	"ClientInfo\x12\x1a.tiny.v1.ClientInfoRequest\x1a\x1b.tiny.v1.ClientInfoResponseB\x19Z\x17demo-go-tiny/src/tinypbb\x06proto3"
+
+var (
+	file_tiny_proto_rawDescOnce sync.Once
+	file_tiny_proto_rawDescData []byte
+)
+
+func file_tiny_proto_rawDescGZIP() []byte {
+	file_tiny_proto_rawDescOnce.Do(func() {
+		file_tiny_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tiny_proto_rawDesc), len(file_tiny_proto_rawDesc)))
+	})
+	return file_tiny_proto_rawDescData
+}
+
+var file_tiny_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_tiny_proto_goTypes = []any{
+	(*EchoRequest)(nil),        // 0: tiny.v1.EchoRequest
+	(*EchoResponse)(nil),       // 1: tiny.v1.EchoResponse
+	(*DelayRequest)(nil),       // 2: tiny.v1.DelayRequest
+	(*DelayResponse)(nil),      // 3: tiny.v1.DelayResponse
+	(*StatusRequest)(nil),      // 4: tiny.v1.StatusRequest
+	(*StatusResponse)(nil),     // 5: tiny.v1.StatusResponse
+	(*ClientInfoRequest)(nil),  // 6: tiny.v1.ClientInfoRequest
+	(*ClientInfoResponse)(nil), // 7: tiny.v1.ClientInfoResponse
+	nil,                        // 8: tiny.v1.EchoResponse.MetadataEntry
+	nil,                        // 9: tiny.v1.ClientInfoResponse.MetadataEntry
+}
+var file_tiny_proto_depIdxs = []int32{
+	8, // 0: tiny.v1.EchoResponse.metadata:type_name -> tiny.v1.EchoResponse.MetadataEntry
+	9, // 1: tiny.v1.ClientInfoResponse.metadata:type_name -> tiny.v1.ClientInfoResponse.MetadataEntry
+	0, // 2: tiny.v1.Tiny.Echo:input_type -> tiny.v1.EchoRequest
+	2, // 3: tiny.v1.Tiny.Delay:input_type -> tiny.v1.DelayRequest
+	4, // 4: tiny.v1.Tiny.Status:input_type -> tiny.v1.StatusRequest
+	6, // 5: tiny.v1.Tiny.ClientInfo:input_type -> tiny.v1.ClientInfoRequest
+	1, // 6: tiny.v1.Tiny.Echo:output_type -> tiny.v1.EchoResponse
+	3, // 7: tiny.v1.Tiny.Delay:output_type -> tiny.v1.DelayResponse
+	5, // 8: tiny.v1.Tiny.Status:output_type -> tiny.v1.StatusResponse
+	7, // 9: tiny.v1.Tiny.ClientInfo:output_type -> tiny.v1.ClientInfoResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_tiny_proto_init() }
+func file_tiny_proto_init() {
+	if File_tiny_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tiny_proto_rawDesc), len(file_tiny_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tiny_proto_goTypes,
+		DependencyIndexes: file_tiny_proto_depIdxs,
+		MessageInfos:      file_tiny_proto_msgTypes,
+	}.Build()
+	File_tiny_proto = out.File
+	file_tiny_proto_goTypes = nil
+	file_tiny_proto_depIdxs = nil
+}
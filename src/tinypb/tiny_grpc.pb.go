@@ -0,0 +1,245 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tiny.proto
+
+// 与 HTTP 接口对应的 gRPC 服务，供 APISIX grpc 上游、grpc-transcode 与 K8s gRPC 探针验证
+
+package tinypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Tiny_Echo_FullMethodName       = "/tiny.v1.Tiny/Echo"
+	Tiny_Delay_FullMethodName      = "/tiny.v1.Tiny/Delay"
+	Tiny_Status_FullMethodName     = "/tiny.v1.Tiny/Status"
+	Tiny_ClientInfo_FullMethodName = "/tiny.v1.Tiny/ClientInfo"
+)
+
+// TinyClient is the client API for Tiny service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TinyClient interface {
+	// 回显消息与请求 metadata，对应 /echo
+	Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error)
+	// 模拟延迟，对应 /delay
+	Delay(ctx context.Context, in *DelayRequest, opts ...grpc.CallOption) (*DelayResponse, error)
+	// 按指定 gRPC 状态码返回，用于验证网关错误码映射
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// 客户端与实例信息，对应 /ip 与 /env
+	ClientInfo(ctx context.Context, in *ClientInfoRequest, opts ...grpc.CallOption) (*ClientInfoResponse, error)
+}
+
+type tinyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTinyClient(cc grpc.ClientConnInterface) TinyClient {
+	return &tinyClient{cc}
+}
+
+func (c *tinyClient) Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EchoResponse)
+	err := c.cc.Invoke(ctx, Tiny_Echo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyClient) Delay(ctx context.Context, in *DelayRequest, opts ...grpc.CallOption) (*DelayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DelayResponse)
+	err := c.cc.Invoke(ctx, Tiny_Delay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, Tiny_Status_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyClient) ClientInfo(ctx context.Context, in *ClientInfoRequest, opts ...grpc.CallOption) (*ClientInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientInfoResponse)
+	err := c.cc.Invoke(ctx, Tiny_ClientInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TinyServer is the server API for Tiny service.
+// All implementations must embed UnimplementedTinyServer
+// for forward compatibility.
+type TinyServer interface {
+	// 回显消息与请求 metadata，对应 /echo
+	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
+	// 模拟延迟，对应 /delay
+	Delay(context.Context, *DelayRequest) (*DelayResponse, error)
+	// 按指定 gRPC 状态码返回，用于验证网关错误码映射
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	// 客户端与实例信息，对应 /ip 与 /env
+	ClientInfo(context.Context, *ClientInfoRequest) (*ClientInfoResponse, error)
+	mustEmbedUnimplementedTinyServer()
+}
+
+// UnimplementedTinyServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTinyServer struct{}
+
+func (UnimplementedTinyServer) Echo(context.Context, *EchoRequest) (*EchoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Echo not implemented")
+}
+func (UnimplementedTinyServer) Delay(context.Context, *DelayRequest) (*DelayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delay not implemented")
+}
+func (UnimplementedTinyServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedTinyServer) ClientInfo(context.Context, *ClientInfoRequest) (*ClientInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClientInfo not implemented")
+}
+func (UnimplementedTinyServer) mustEmbedUnimplementedTinyServer() {}
+func (UnimplementedTinyServer) testEmbeddedByValue()              {}
+
+// UnsafeTinyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TinyServer will
+// result in compilation errors.
+type UnsafeTinyServer interface {
+	mustEmbedUnimplementedTinyServer()
+}
+
+func RegisterTinyServer(s grpc.ServiceRegistrar, srv TinyServer) {
+	// If the following call pancis, it indicates UnimplementedTinyServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Tiny_ServiceDesc, srv)
+}
+
+func _Tiny_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyServer).Echo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tiny_Echo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyServer).Echo(ctx, req.(*EchoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tiny_Delay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyServer).Delay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tiny_Delay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyServer).Delay(ctx, req.(*DelayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tiny_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tiny_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tiny_ClientInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyServer).ClientInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tiny_ClientInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyServer).ClientInfo(ctx, req.(*ClientInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Tiny_ServiceDesc is the grpc.ServiceDesc for Tiny service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tiny_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tiny.v1.Tiny",
+	HandlerType: (*TinyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Echo",
+			Handler:    _Tiny_Echo_Handler,
+		},
+		{
+			MethodName: "Delay",
+			Handler:    _Tiny_Delay_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _Tiny_Status_Handler,
+		},
+		{
+			MethodName: "ClientInfo",
+			Handler:    _Tiny_ClientInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tiny.proto",
+}
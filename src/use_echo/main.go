@@ -2,7 +2,6 @@ package use_echo
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"math/rand"
 	"net/http"
@@ -14,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"demo-go-tiny/src/common"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -44,7 +45,7 @@ func StartServer() {
 		port = "8080"
 	}
 	log.Printf("Echo server listening on :%s", port)
-	e.Logger.Fatal(e.Start(":" + port))
+	e.Logger.Fatal(common.ListenAndServe("echo", ":"+port, e))
 }
 
 // registerRoutes 注册所有路由
@@ -56,6 +57,34 @@ func registerRoutes(e *echo.Echo) {
 	e.GET("/delay", delayHandler)
 	e.GET("/mem", memHandler)
 	e.GET("/cpu", cpuHandler)
+	e.Match([]string{"GET", "HEAD"}, "/cache", echo.WrapHandler(http.HandlerFunc(common.Cache)))
+	e.Match([]string{"GET", "HEAD"}, "/cache/:seconds", echo.WrapHandler(http.HandlerFunc(common.Cache)))
+	e.GET("/compressed/:encoding", echo.WrapHandler(http.HandlerFunc(common.Compressed)))
+	e.Match([]string{"GET", "HEAD"}, "/bytes/:n", echo.WrapHandler(http.HandlerFunc(common.Bytes)))
+	e.Match([]string{"GET", "HEAD"}, "/stream-bytes/:n", echo.WrapHandler(http.HandlerFunc(common.StreamBytes)))
+	e.GET("/ws", echo.WrapHandler(http.HandlerFunc(common.WebSocket)))
+	e.GET("/metrics", echo.WrapHandler(http.HandlerFunc(common.Metrics)))
+	e.GET("/sse", echo.WrapHandler(http.HandlerFunc(common.SSE)))
+	e.GET("/tls", echo.WrapHandler(http.HandlerFunc(common.TLS)))
+	e.GET("/tls/ca", echo.WrapHandler(http.HandlerFunc(common.TLSCA)))
+	e.GET("/tls/client-cert", echo.WrapHandler(http.HandlerFunc(common.TLSClientCert)))
+	e.GET("/stream/stats", echo.WrapHandler(http.HandlerFunc(common.StreamStats)))
+	e.GET("/session", echo.WrapHandler(http.HandlerFunc(common.Session)))
+	e.Match([]string{"GET", "DELETE"}, "/admin/requests", echo.WrapHandler(http.HandlerFunc(common.Requests)))
+	e.GET("/admin/requests/:id", echo.WrapHandler(http.HandlerFunc(common.Requests)))
+	e.GET("/health/upstream", echo.WrapHandler(http.HandlerFunc(common.HealthUpstream)))
+	e.Match([]string{"GET", "POST", "PUT"}, "/admin/health", echo.WrapHandler(http.HandlerFunc(common.HealthAdmin)))
+	e.Any("/flaky", echo.WrapHandler(http.HandlerFunc(common.Flaky)))
+	e.GET("/slow/:mode", echo.WrapHandler(http.HandlerFunc(common.Slow)))
+	e.GET("/abort/:mode", echo.WrapHandler(http.HandlerFunc(common.Abort)))
+	e.Match([]string{"POST", "PUT"}, "/upload", echo.WrapHandler(http.HandlerFunc(common.Upload)))
+	e.GET("/work", echo.WrapHandler(http.HandlerFunc(common.Work)))
+	e.GET("/cpu/:kind", echo.WrapHandler(http.HandlerFunc(common.CPULoad)))
+	e.Any("/anything", echo.WrapHandler(http.HandlerFunc(common.Anything)))
+	e.Any("/anything/*", echo.WrapHandler(http.HandlerFunc(common.Anything)))
+	if common.AnythingFallback {
+		e.RouteNotFound("/*", echo.WrapHandler(http.HandlerFunc(common.Anything)))
+	}
 	e.GET("/", rootHandler)
 }
 
@@ -66,19 +95,11 @@ func pingHandler(c echo.Context) error {
 
 // ---------- 2. 回显 ----------
 func echoHandler(c echo.Context) error {
-	var body []byte
-	if c.Request().Method == http.MethodPost {
-		body, _ = io.ReadAll(c.Request().Body)
+	data, err := common.EchoData(c.Request())
+	if err != nil {
+		return c.JSON(http.StatusRequestEntityTooLarge, resp{Code: 1, Msg: err.Error()})
 	}
-	return c.JSON(http.StatusOK, resp{
-		Code: 0,
-		Data: map[string]interface{}{
-			"method":  c.Request().Method,
-			"query":   c.Request().URL.Query(),
-			"body":    string(body),
-			"headers": c.Request().Header,
-		},
-	})
+	return c.JSON(http.StatusOK, resp{Code: 0, Data: data})
 }
 
 // ---------- 3. 客户端 IP ----------
@@ -197,13 +218,9 @@ func cpuHandler(c echo.Context) error {
 		}()
 	}
 
-	// 异步等待完成
-	go func() {
-		wg.Wait()
-		c.JSON(http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
-	}()
-
-	return c.NoContent(http.StatusAccepted)
+	// 等待完成后再返回，请求在负载期间一直占用 MAX_INFLIGHT 的名额
+	wg.Wait()
+	return c.JSON(http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
 }
 
 // cpuIntensiveTask 执行CPU密集型任务
@@ -232,7 +249,10 @@ func cpuIntensiveTask(duration time.Duration, percent int) {
 
 // ---------- 8. 根路径提示 ----------
 func rootHandler(c echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]string{
-		"routes": "/ping /echo /ip /env /delay?ms=100 /mem?mb=10&ms=10000 /cpu?ms=1000&cores=2&percent=80",
-	})
+	var routes []common.Route
+	for _, r := range c.Echo().Routes() {
+		routes = append(routes, common.Route{Method: r.Method, Path: r.Path})
+	}
+	common.Root(c.Response(), c.Request(), "echo", routes)
+	return nil
 }
\ No newline at end of file
@@ -2,7 +2,6 @@ package use_gin
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"math/rand"
 	"net/http"
@@ -14,6 +13,8 @@ import (
 	"sync"
 	"time"
 
+	"demo-go-tiny/src/common"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -39,19 +40,12 @@ func StartServer() {
 
 	// ---------- 2. 回显 ----------
 	r.Any("/echo", func(c *gin.Context) {
-		var body []byte
-		if c.Request.Method == http.MethodPost {
-			body, _ = io.ReadAll(c.Request.Body)
+		data, err := common.EchoData(c.Request)
+		if err != nil {
+			c.JSON(http.StatusRequestEntityTooLarge, resp{Code: 1, Msg: err.Error()})
+			return
 		}
-		c.JSON(http.StatusOK, resp{
-			Code: 0,
-			Data: map[string]interface{}{
-				"method":  c.Request.Method,
-				"query":   c.Request.URL.Query(),
-				"body":    string(body),
-				"headers": c.Request.Header,
-			},
-		})
+		c.JSON(http.StatusOK, resp{Code: 0, Data: data})
 	})
 
 	// ---------- 3. 客户端 IP ----------
@@ -182,18 +176,60 @@ func StartServer() {
 			}()
 		}
 
-		// 异步等待并返回结果
-		go func() {
-			wg.Wait()
-			c.JSON(http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
-		}()
+		// 等待完成后再返回，请求在负载期间一直占用 MAX_INFLIGHT 的名额
+		wg.Wait()
+		c.JSON(http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
 	})
 
+	// ---------- HTTP 缓存语义 ----------
+	r.Match([]string{"GET", "HEAD"}, "/cache", gin.WrapF(common.Cache))
+	r.Match([]string{"GET", "HEAD"}, "/cache/:seconds", gin.WrapF(common.Cache))
+
+	// ---------- 响应压缩 ----------
+	r.GET("/compressed/:encoding", gin.WrapF(common.Compressed))
+
+	// ---------- 大响应体生成 ----------
+	r.Match([]string{"GET", "HEAD"}, "/bytes/:n", gin.WrapF(common.Bytes))
+	r.Match([]string{"GET", "HEAD"}, "/stream-bytes/:n", gin.WrapF(common.StreamBytes))
+
+	// ---------- WebSocket 与指标 ----------
+	r.GET("/ws", gin.WrapF(common.WebSocket))
+	r.GET("/metrics", gin.WrapF(common.Metrics))
+
+	// ---------- Server-Sent Events ----------
+	r.GET("/sse", gin.WrapF(common.SSE))
+
+	// ---------- TLS 与客户端证书 ----------
+	r.GET("/tls", gin.WrapF(common.TLS))
+	r.GET("/tls/ca", gin.WrapF(common.TLSCA))
+	r.GET("/tls/client-cert", gin.WrapF(common.TLSClientCert))
+
+	// ---------- TCP / UDP 连接统计 ----------
+	r.GET("/stream/stats", gin.WrapF(common.StreamStats))
+	r.GET("/session", gin.WrapF(common.Session))
+	r.Match([]string{"GET", "DELETE"}, "/admin/requests", gin.WrapF(common.Requests))
+	r.GET("/admin/requests/:id", gin.WrapF(common.Requests))
+	r.GET("/health/upstream", gin.WrapF(common.HealthUpstream))
+	r.Match([]string{"GET", "POST", "PUT"}, "/admin/health", gin.WrapF(common.HealthAdmin))
+	r.Any("/flaky", gin.WrapF(common.Flaky))
+	r.GET("/slow/:mode", gin.WrapF(common.Slow))
+	r.GET("/abort/:mode", gin.WrapF(common.Abort))
+	r.Match([]string{"POST", "PUT"}, "/upload", gin.WrapF(common.Upload))
+	r.GET("/work", gin.WrapF(common.Work))
+	r.GET("/cpu/:kind", gin.WrapF(common.CPULoad))
+	r.Any("/anything", gin.WrapF(common.Anything))
+	r.Any("/anything/*path", gin.WrapF(common.Anything))
+	if common.AnythingFallback {
+		r.NoRoute(gin.WrapF(common.Anything))
+	}
+
 	// ---------- 8. 根路径提示 ----------
 	r.GET("/", func(c *gin.Context) {
-		c.JSON(http.StatusOK, map[string]string{
-			"routes": "/ping /echo /ip /env /delay?ms=100 /mem?mb=10&ms=10000 /cpu?ms=1000&cores=2&percent=80",
-		})
+		var routes []common.Route
+		for _, ri := range r.Routes() {
+			routes = append(routes, common.Route{Method: ri.Method, Path: ri.Path})
+		}
+		common.Root(c.Writer, c.Request, "gin", routes)
 	})
 
 	// 监听端口
@@ -202,7 +238,7 @@ func StartServer() {
 		port = "8080"
 	}
 	log.Printf("Gin server listening on :%s", port)
-	if err := r.Run(":" + port); err != nil {
+	if err := common.ListenAndServe("gin", ":"+port, r); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
@@ -0,0 +1,140 @@
+package use_grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"demo-go-tiny/src/common"
+	"demo-go-tiny/src/tinypb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// server 实现 tiny.v1.Tiny
+type server struct {
+	tinypb.UnimplementedTinyServer
+}
+
+// StartServer 启动 gRPC 服务（Tiny + grpc.health.v1.Health + 反射），与 HTTP 服务并行运行
+func StartServer(port string) {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatalf("Failed to listen gRPC on :%s: %v", port, err)
+	}
+
+	s := grpc.NewServer(grpc.UnaryInterceptor(logInterceptor))
+	tinypb.RegisterTinyServer(s, &server{})
+
+	// 标准健康检查服务，供 K8s grpc 探针与 APISIX 使用
+	hs := health.NewServer()
+	hs.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	hs.SetServingStatus(tinypb.Tiny_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(s, hs)
+
+	// 服务反射，便于 grpcurl 直接调用
+	reflection.Register(s)
+
+	log.Printf("gRPC server listening on :%s", port)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve gRPC: %v", err)
+	}
+}
+
+// logInterceptor 打印每次调用的方法、耗时与状态码
+func logInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	addr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	log.Printf("[gRPC] %s | %s | %v | %s", status.Code(err), addr, time.Since(start), info.FullMethod)
+	return resp, err
+}
+
+// flatMetadata 将 metadata 多值以逗号拼接
+func flatMetadata(ctx context.Context) map[string]string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	m := make(map[string]string, len(md))
+	for k, v := range md {
+		m[k] = strings.Join(v, ",")
+	}
+	return m
+}
+
+// ---------- 1. 回显 ----------
+func (s *server) Echo(ctx context.Context, req *tinypb.EchoRequest) (*tinypb.EchoResponse, error) {
+	return &tinypb.EchoResponse{
+		Message:  req.GetMessage(),
+		Metadata: flatMetadata(ctx),
+		Pod:      common.PodName(),
+		Version:  common.Version(),
+	}, nil
+}
+
+// ---------- 2. 延迟模拟 ----------
+func (s *server) Delay(ctx context.Context, req *tinypb.DelayRequest) (*tinypb.DelayResponse, error) {
+	ms := req.GetMs()
+	if ms <= 0 {
+		ms = 100
+	}
+	select {
+	case <-time.After(time.Duration(ms) * time.Millisecond):
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return &tinypb.DelayResponse{Msg: fmt.Sprintf("slept %dms", ms), SleptMs: ms}, nil
+}
+
+// ---------- 3. 指定状态码 ----------
+func (s *server) Status(_ context.Context, req *tinypb.StatusRequest) (*tinypb.StatusResponse, error) {
+	code := codes.Code(req.GetCode())
+	if code == codes.OK {
+		return &tinypb.StatusResponse{Msg: "ok"}, nil
+	}
+	msg := req.GetMessage()
+	if msg == "" {
+		msg = code.String()
+	}
+	return nil, status.Error(code, msg)
+}
+
+// ---------- 4. 客户端与实例信息 ----------
+func (s *server) ClientInfo(ctx context.Context, _ *tinypb.ClientInfoRequest) (*tinypb.ClientInfoResponse, error) {
+	md := flatMetadata(ctx)
+	resp := &tinypb.ClientInfoResponse{
+		Authority: md[":authority"],
+		UserAgent: md["user-agent"],
+		Metadata:  md,
+		PodName:   common.PodName(),
+		NodeName:  os.Getenv("NODE_NAME"),
+		Version:   common.Version(),
+		StartTime: common.StartTime.Format(time.RFC3339),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		resp.Peer = p.Addr.String()
+	}
+
+	// 与 HTTP 的 clientIP 保持一致：X-Forwarded-For > X-Real-Ip > 对端地址
+	switch {
+	case md["x-forwarded-for"] != "":
+		resp.ClientIp = strings.TrimSpace(strings.Split(md["x-forwarded-for"], ",")[0])
+	case md["x-real-ip"] != "":
+		resp.ClientIp = md["x-real-ip"]
+	default:
+		resp.ClientIp, _, _ = net.SplitHostPort(resp.Peer)
+	}
+	return resp, nil
+}
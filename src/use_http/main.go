@@ -3,7 +3,6 @@ package use_http
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"math/rand"
 	"net/http"
@@ -14,6 +13,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"demo-go-tiny/src/common"
 )
 
 // 统一 JSON 返回
@@ -36,19 +37,12 @@ func ping(w http.ResponseWriter, _ *http.Request) {
 
 // ---------- 2. 回显 ----------
 func echo(w http.ResponseWriter, r *http.Request) {
-	var body []byte
-	if r.Method == http.MethodPost {
-		body, _ = io.ReadAll(r.Body)
+	data, err := common.EchoData(r)
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, resp{Code: 1, Msg: err.Error()})
+		return
 	}
-	writeJSON(w, http.StatusOK, resp{
-		Code: 0,
-		Data: map[string]interface{}{
-			"method":  r.Method,
-			"query":   r.URL.Query(),
-			"body":    string(body),
-			"headers": r.Header,
-		},
-	})
+	writeJSON(w, http.StatusOK, resp{Code: 0, Data: data})
 }
 
 // ---------- 3. 客户端 IP ----------
@@ -189,17 +183,30 @@ func cpu(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
-	// 等待所有goroutine完成
-	go func() {
-		wg.Wait()
-		writeJSON(w, http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
-	}()
+	// 等待所有goroutine完成后再返回，请求在负载期间一直占用 MAX_INFLIGHT 的名额
+	wg.Wait()
+	writeJSON(w, http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
 }
 
 var startTime = time.Now()
 
+// router 记录已注册的路由，http.ServeMux 本身无法遍历
+type router struct {
+	*http.ServeMux
+	routes []common.Route
+}
+
+func (rt *router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	rt.ServeMux.HandleFunc(pattern, handler)
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, path = "*", pattern
+	}
+	rt.routes = append(rt.routes, common.Route{Method: method, Path: path})
+}
+
 func StartServer() {
-	mux := http.NewServeMux()
+	mux := &router{ServeMux: http.NewServeMux()}
 	mux.HandleFunc("/ping", ping)
 	mux.HandleFunc("/echo", echo)
 	mux.HandleFunc("/ip", ip)
@@ -207,12 +214,44 @@ func StartServer() {
 	mux.HandleFunc("/delay", delay)
 	mux.HandleFunc("/mem", mem)
 	mux.HandleFunc("/cpu", cpu) // 添加CPU占用路由
+	mux.HandleFunc("/cache", common.Cache)
+	mux.HandleFunc("/cache/", common.Cache)
+	mux.HandleFunc("/compressed/", common.Compressed)
+	mux.HandleFunc("/bytes/", common.Bytes)
+	mux.HandleFunc("/stream-bytes/", common.StreamBytes)
+	mux.HandleFunc("/ws", common.WebSocket)
+	mux.HandleFunc("/metrics", common.Metrics)
+	mux.HandleFunc("/sse", common.SSE)
+	mux.HandleFunc("/tls", common.TLS)
+	mux.HandleFunc("/tls/ca", common.TLSCA)
+	mux.HandleFunc("/tls/client-cert", common.TLSClientCert)
+	mux.HandleFunc("/stream/stats", common.StreamStats)
+	mux.HandleFunc("/session", common.Session)
+	mux.HandleFunc("/admin/requests", common.Requests)
+	mux.HandleFunc("/admin/requests/", common.Requests)
+	mux.HandleFunc("/health/upstream", common.HealthUpstream)
+	mux.HandleFunc("/admin/health", common.HealthAdmin)
+	mux.HandleFunc("/flaky", common.Flaky)
+	mux.HandleFunc("/slow/", common.Slow)
+	mux.HandleFunc("/abort/", common.Abort)
+	mux.HandleFunc("/upload", common.Upload)
+	mux.HandleFunc("/work", common.Work)
+	mux.HandleFunc("/cpu/", common.CPULoad)
+	mux.HandleFunc("/anything", common.Anything)
+	mux.HandleFunc("/anything/", common.Anything)
 
 	// 7. 根路径提示
-	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
-		writeJSON(w, http.StatusOK, map[string]string{
-			"routes": "/ping /echo /ip /env /delay?ms=100 /mem?mb=10&ms=10000 /cpu?ms=1000&cores=2&percent=80",
-		})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// "/" 会匹配所有未注册的路径
+		if r.URL.Path != "/" {
+			if common.AnythingFallback {
+				common.Anything(w, r)
+			} else {
+				http.NotFound(w, r)
+			}
+			return
+		}
+		common.Root(w, r, "http", mux.routes)
 	})
 
 	port := os.Getenv("PORT")
@@ -220,5 +259,5 @@ func StartServer() {
 		port = "8080"
 	}
 	log.Printf("listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	log.Fatal(common.ListenAndServe("http", ":"+port, mux))
 }
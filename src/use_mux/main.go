@@ -3,7 +3,6 @@ package use_mux
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"math/rand"
 	"net/http"
@@ -15,6 +14,8 @@ import (
 	"sync"
 	"time"
 
+	"demo-go-tiny/src/common"
+
 	"github.com/gorilla/mux"
 )
 
@@ -47,7 +48,7 @@ func StartServer() {
 		port = "8080"
 	}
 	log.Printf("Gorilla Mux server listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	log.Fatal(common.ListenAndServe("mux", ":"+port, router))
 }
 
 // registerRoutes 注册所有路由
@@ -59,7 +60,35 @@ func registerRoutes(router *mux.Router) {
 	router.HandleFunc("/delay", delayHandler).Methods("GET")
 	router.HandleFunc("/mem", memHandler).Methods("GET")
 	router.HandleFunc("/cpu", cpuHandler).Methods("GET")
-	router.HandleFunc("/", rootHandler).Methods("GET")
+	router.HandleFunc("/cache", common.Cache).Methods("GET", "HEAD")
+	router.HandleFunc("/cache/{seconds}", common.Cache).Methods("GET", "HEAD")
+	router.HandleFunc("/compressed/{encoding}", common.Compressed).Methods("GET")
+	router.HandleFunc("/bytes/{n}", common.Bytes).Methods("GET", "HEAD")
+	router.HandleFunc("/stream-bytes/{n}", common.StreamBytes).Methods("GET", "HEAD")
+	router.HandleFunc("/ws", common.WebSocket).Methods("GET")
+	router.HandleFunc("/metrics", common.Metrics).Methods("GET")
+	router.HandleFunc("/sse", common.SSE).Methods("GET")
+	router.HandleFunc("/tls", common.TLS).Methods("GET")
+	router.HandleFunc("/tls/ca", common.TLSCA).Methods("GET")
+	router.HandleFunc("/tls/client-cert", common.TLSClientCert).Methods("GET")
+	router.HandleFunc("/stream/stats", common.StreamStats).Methods("GET")
+	router.HandleFunc("/session", common.Session).Methods("GET")
+	router.HandleFunc("/admin/requests", common.Requests).Methods("GET", "DELETE")
+	router.HandleFunc("/admin/requests/{id}", common.Requests).Methods("GET")
+	router.HandleFunc("/health/upstream", common.HealthUpstream).Methods("GET")
+	router.HandleFunc("/admin/health", common.HealthAdmin).Methods("GET", "POST", "PUT")
+	router.HandleFunc("/flaky", common.Flaky)
+	router.HandleFunc("/slow/{mode}", common.Slow).Methods("GET")
+	router.HandleFunc("/abort/{mode}", common.Abort).Methods("GET")
+	router.HandleFunc("/upload", common.Upload).Methods("POST", "PUT")
+	router.HandleFunc("/work", common.Work).Methods("GET")
+	router.HandleFunc("/cpu/{kind}", common.CPULoad).Methods("GET")
+	router.HandleFunc("/anything", common.Anything)
+	router.PathPrefix("/anything/").HandlerFunc(common.Anything)
+	if common.AnythingFallback {
+		router.NotFoundHandler = http.HandlerFunc(common.Anything)
+	}
+	router.HandleFunc("/", rootHandler(router)).Methods("GET")
 }
 
 // ---------- 1. 探活 ----------
@@ -69,19 +98,12 @@ func pingHandler(w http.ResponseWriter, r *http.Request) {
 
 // ---------- 2. 回显 ----------
 func echoHandler(w http.ResponseWriter, r *http.Request) {
-	var body []byte
-	if r.Method == http.MethodPost {
-		body, _ = io.ReadAll(r.Body)
+	data, err := common.EchoData(r)
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, resp{Code: 1, Msg: err.Error()})
+		return
 	}
-	writeJSON(w, http.StatusOK, resp{
-		Code: 0,
-		Data: map[string]interface{}{
-			"method":  r.Method,
-			"query":   r.URL.Query(),
-			"body":    string(body),
-			"headers": r.Header,
-		},
-	})
+	writeJSON(w, http.StatusOK, resp{Code: 0, Data: data})
 }
 
 // ---------- 3. 客户端 IP ----------
@@ -200,13 +222,9 @@ func cpuHandler(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
-	// 异步等待完成
-	go func() {
-		wg.Wait()
-		writeJSON(w, http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
-	}()
-
-	w.WriteHeader(http.StatusAccepted)
+	// 等待完成后再返回，请求在负载期间一直占用 MAX_INFLIGHT 的名额
+	wg.Wait()
+	writeJSON(w, http.StatusOK, resp{Code: 0, Msg: fmt.Sprintf("CPU test completed: %d core(s) at %d%% for %s", cores, percent, duration)})
 }
 
 // cpuIntensiveTask 执行CPU密集型任务
@@ -234,8 +252,23 @@ func cpuIntensiveTask(duration time.Duration, percent int) {
 }
 
 // ---------- 8. 根路径提示 ----------
-func rootHandler(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]string{
-		"routes": "/ping /echo /ip /env /delay?ms=100 /mem?mb=10&ms=10000 /cpu?ms=1000&cores=2&percent=80",
-	})
+func rootHandler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var routes []common.Route
+		_ = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			path, err := route.GetPathTemplate()
+			if err != nil {
+				return nil
+			}
+			methods, _ := route.GetMethods()
+			if len(methods) == 0 {
+				methods = []string{"*"}
+			}
+			for _, m := range methods {
+				routes = append(routes, common.Route{Method: m, Path: path})
+			}
+			return nil
+		})
+		common.Root(w, r, "mux", routes)
+	}
 }